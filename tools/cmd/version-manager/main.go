@@ -125,8 +125,8 @@ func performSync(cfg *VersionConfig, dryRun bool) error {
 		changesCount++
 	}
 
-	// 2. Update Go Constant (tools/mcp-server/internal/adapter/mcp/server.go) - Protocol Version
-	changed, err = updateMcpProtocol("tools/mcp-server/internal/adapter/mcp/server.go", cfg.McpProtocolVersion, dryRun)
+	// 2. Update Go Constant (tools/mcp-server/internal/adapter/mcp/protocol.go) - Latest Protocol Version
+	changed, err = updateMcpProtocol("tools/mcp-server/internal/adapter/mcp/protocol.go", cfg.McpProtocolVersion, dryRun)
 	if err != nil {
 		// Non-fatal if file structure changed, but good to know
 		fmt.Printf("Warning: Could not update MCP protocol version: %v\n", err)
@@ -193,16 +193,16 @@ func updateMcpProtocol(path string, version string, dryRun bool) (bool, error) {
 		return false, err
 	}
 
-	// Look for ProtocolVersion: "..." in struct initialization
-	re := regexp.MustCompile(`ProtocolVersion:\s*".*"`)
-	newContent := re.ReplaceAll(content, []byte(fmt.Sprintf(`ProtocolVersion: "%s"`, version)))
+	// Look for const latestProtocolVersion = "..."
+	re := regexp.MustCompile(`const latestProtocolVersion = "[^"]+"`)
+	newContent := re.ReplaceAll(content, []byte(fmt.Sprintf(`const latestProtocolVersion = "%s"`, version)))
 
 	if !bytes.Equal(content, newContent) {
 		if dryRun {
-			fmt.Printf("[CHECK] ProtocolVersion in %s would be updated to %s\n", path, version)
+			fmt.Printf("[CHECK] latestProtocolVersion in %s would be updated to %s\n", path, version)
 			return true, nil
 		}
-		fmt.Printf("Updating ProtocolVersion in %s -> %s\n", path, version)
+		fmt.Printf("Updating latestProtocolVersion in %s -> %s\n", path, version)
 		return true, os.WriteFile(path, newContent, 0644)
 	}
 	return false, nil
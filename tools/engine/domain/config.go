@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type Config struct {
 	// Global Settings
 	ASDPVersion    string   `yaml:"asdp_version"`
@@ -31,17 +33,32 @@ type Config struct {
 type SystemConfig struct {
 	GlobalAssetsDir string `yaml:"global_assets_dir"` // ~/.asdp/core/agent
 	DefaultAgentDir string `yaml:"default_agent_dir"` // .agent
+	LogDir          string `yaml:"log_dir"`           // ~/.asdp/logs
+	LogLevel        string `yaml:"log_level"`         // debug, info, warn, error
+	CacheDir        string `yaml:"cache_dir"`         // <project>/.asdp/cache
 }
 
 type ParsingConfig struct {
-	Go          GoParsingConfig    `yaml:"go"`
-	Ctags       CtagsParsingConfig `yaml:"ctags"`
-	SkipHidden  bool               `yaml:"skip_hidden"`
-	IgnoreFiles []string           `yaml:"ignore_files"`
+	Go          GoParsingConfig     `yaml:"go"`
+	Python      NativeParsingConfig `yaml:"python"`
+	JavaScript  NativeParsingConfig `yaml:"javascript"` // JavaScript and TypeScript
+	Ctags       CtagsParsingConfig  `yaml:"ctags"`
+	SkipHidden  bool                `yaml:"skip_hidden"`
+	IgnoreFiles []string            `yaml:"ignore_files"`
 }
 
 type GoParsingConfig struct {
 	SkipTests bool `yaml:"skip_tests"`
+	Fields    bool `yaml:"fields"`     // Emit struct fields as child symbols of their struct (interface methods always are)
+	TypeCheck bool `yaml:"type_check"` // Record imports, references and implemented interfaces (slower; disables incremental syncs)
+	CallGraph bool `yaml:"call_graph"` // Record the calls of each function to the rest of its module
+}
+
+// NativeParsingConfig enables a built-in parser for the files with the given extensions;
+// ctags then leaves them alone.
+type NativeParsingConfig struct {
+	Enabled    bool     `yaml:"enabled"`
+	Extensions []string `yaml:"extensions"` // e.g. [.py, .pyi]
 }
 
 type CtagsParsingConfig struct {
@@ -75,6 +92,7 @@ type MCPConfig struct {
 type ToolMetadata struct {
 	Description string                 `yaml:"description"`
 	InputSchema map[string]interface{} `yaml:"input_schema"`
+	Disabled    bool                   `yaml:"disabled"` // Hide the tool from tools/list and reject calls
 }
 
 type ScaffoldConfig struct {
@@ -87,6 +105,7 @@ type ScaffoldConfig struct {
 type SyncConfig struct {
 	Tree  TreeSyncConfig  `yaml:"tree"`
 	Model ModelSyncConfig `yaml:"model"`
+	Watch WatchConfig     `yaml:"watch"`
 }
 
 type TreeSyncConfig struct {
@@ -102,6 +121,15 @@ type ModelSyncConfig struct {
 	IntegrityAlgo  string `yaml:"integrity_algo"`  // "sha256"
 	FirstSyncHash  string `yaml:"first_sync_hash"` // "PENDING_FIRST_SYNC"
 	HeaderTemplate string `yaml:"header_template"` // "Semantic Model..."
+	FileManifest   bool   `yaml:"file_manifest"`   // Record per-file hashes: precise drift reports, incremental re-parsing
+}
+
+// WatchConfig tunes watch mode (asdp-mcp-server --watch).
+type WatchConfig struct {
+	Mode         string        `yaml:"mode"`          // "sync" re-syncs stale codemodels, "notify" only reports them
+	Backend      string        `yaml:"backend"`       // "auto" (inotify where available), "inotify", "poll"
+	Debounce     time.Duration `yaml:"debounce"`      // Quiet time before a burst of changes is handled
+	PollInterval time.Duration `yaml:"poll_interval"` // For the poll backend
 }
 
 type ValidationConfig struct {
@@ -124,7 +152,7 @@ func DefaultConfig() *Config {
 		"node_modules", "vendor", "packages", "bower_components",
 		"venv", ".venv", "anaconda", "conda", "env", ".env",
 		"bin", "obj", "dist", "target", "build", "out",
-		".vscode", ".idea", ".git", ".hg", ".svn", ".cache",
+		".vscode", ".idea", ".git", ".hg", ".svn", ".cache", ".asdp",
 		"__pycache__", "structs",
 	}
 
@@ -137,6 +165,14 @@ func DefaultConfig() *Config {
 			Go: GoParsingConfig{
 				SkipTests: true,
 			},
+			Python: NativeParsingConfig{
+				Enabled:    true,
+				Extensions: []string{".py", ".pyi"},
+			},
+			JavaScript: NativeParsingConfig{
+				Enabled:    true,
+				Extensions: []string{".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".mts", ".cts"},
+			},
 			Ctags: CtagsParsingConfig{
 				Binary:       "ctags",
 				Recurse:      false,
@@ -159,152 +195,10 @@ func DefaultConfig() *Config {
 			AutoGeneratedTree:   "\n# Project Hierarchy\n\nAuto-generated by ASDP SyncTree.\n",
 		},
 		MCP: MCPConfig{
-			ProtocolVersion: "2024-11-05",
-			ToolDefinitions: map[string]ToolMetadata{
-				"asdp_query_context": {
-					Description: "Retrieve the ASDP context (Spec, Model, Freshness) for a given absolute path. Result: Returns a JSON object containing the merged CodeSpec (intent), CodeModel (structure), and current freshness status, allowing an agent to quickly understand a module's contract and implementation.",
-					InputSchema: map[string]interface{}{
-						"type": "object",
-						"properties": map[string]interface{}{
-							"path": map[string]interface{}{
-								"type":        "string",
-								"description": "ABSOLUTE path to the module (e.g. /home/user/project/module)",
-							},
-						},
-						"required": []string{"path"},
-					},
-				},
-				"asdp_sync_codemodel": {
-					Description: "Automatically scans the source code and updates the codemodel.md file. Result: Returns a SyncResult JSON with the count of symbols identified (functions, structs, interfaces including start/end lines) and the integrity hash of the source files.",
-					InputSchema: map[string]interface{}{
-						"type": "object",
-						"properties": map[string]interface{}{
-							"path": map[string]interface{}{
-								"type":        "string",
-								"description": "ABSOLUTE path to the module (e.g. /home/user/project/module)",
-							},
-						},
-						"required": []string{"path"},
-					},
-				},
-				"asdp_sync_codetree": {
-					Description: "Automatically scans the project directory and updates the codetree.md file. Result: Returns a JSON representation of the project hierarchy, listing all modules and their ASDP compliance status (presence of codespec/codemodel).",
-					InputSchema: map[string]interface{}{
-						"type": "object",
-						"properties": map[string]interface{}{
-							"path": map[string]interface{}{
-								"type":        "string",
-								"description": "ABSOLUTE path to the project root or sub-directory.",
-							},
-						},
-						"required": []string{"path"},
-					},
-				},
-				"asdp_scaffold": {
-					Description: "Create a new ASDP-compliant module or backfill missing files (codespec/codemodel) in an existing one. Safe to run on existing directories; will not overwrite existing files. Result: Returns a success message.",
-					InputSchema: map[string]interface{}{
-						"type": "object",
-						"properties": map[string]interface{}{
-							"name": map[string]interface{}{
-								"type":        "string",
-								"description": "Module name. Use '.' to scaffold directly in the provided path.",
-							},
-							"type": map[string]interface{}{
-								"type":        "string",
-								"description": "Module type (library, service, app). Default: library",
-							},
-							"path": map[string]interface{}{
-								"type":        "string",
-								"description": "ABSOLUTE parent directory (or target directory if name='.').",
-							},
-							"title": map[string]interface{}{
-								"type":        "string",
-								"description": "Title of the module (e.g. 'User Authentication Service'). Required.",
-							},
-							"summary": map[string]interface{}{
-								"type":        "string",
-								"description": "Brief summary of the module's purpose. Required.",
-							},
-							"context": map[string]interface{}{
-								"type":        "string",
-								"description": "Detailed context and reasoning for this module. Required.",
-							},
-						},
-						"required": []string{"name", "path", "title", "summary", "context"},
-					},
-				},
-				"asdp_init_agent": {
-					Description: "Copies ASDP Agent assets into the local project. Result: Returns a list of files copied into the .agent/ directory (Rules, Workflows, etc.) to enable ASDP-native agent behavior.",
-					InputSchema: map[string]interface{}{
-						"type": "object",
-						"properties": map[string]interface{}{
-							"path": map[string]interface{}{
-								"type":        "string",
-								"description": "ABSOLUTE project root path (e.g. /home/user/project)",
-							},
-						},
-					},
-				},
-				"asdp_init_project": {
-					Description: "Unified initialization of an ASDP project. Result: Sets up .agent/ at project path AND anchors the CodeTree. You MUST analyze the project structure and README first, then provide meaningful Title, Summary, and Context.",
-					InputSchema: map[string]interface{}{
-						"type": "object",
-						"properties": map[string]interface{}{
-							"path": map[string]interface{}{
-								"type":        "string",
-								"description": "ABSOLUTE project repository root path.",
-							},
-							"code_path": map[string]interface{}{
-								"type":        "string",
-								"description": "ABSOLUTE path to where the actual code starts (e.g. /repo/tools).",
-							},
-							"title": map[string]interface{}{
-								"type":        "string",
-								"description": "Descriptive title for the root module. You MUST analyze the project first to generate this.",
-							},
-							"summary": map[string]interface{}{
-								"type":        "string",
-								"description": "Brief summary of the root module. You MUST analyze the project first to generate this.",
-							},
-							"context": map[string]interface{}{
-								"type":        "string",
-								"description": "Detailed context and reasoning for the root module. You MUST analyze the project first to generate this.",
-							},
-						},
-						"required": []string{"path", "code_path", "title", "summary", "context"},
-					},
-				},
-				"asdp_validate": {
-					Description: "Audit the ASDP project state. Returns a report of Errors (invalid state, integration blocking) and Warnings (staleness). Checks for mandatory files, strict content compliance, and synchronization freshness.",
-					InputSchema: map[string]interface{}{
-						"type": "object",
-						"properties": map[string]interface{}{
-							"path": map[string]interface{}{
-								"type":        "string",
-								"description": "ABSOLUTE path to the project root.",
-							},
-						},
-						"required": []string{"path"},
-					},
-				},
-				"asdp_function_info": {
-					Description: "Retrieve detailed information about a function/symbol, including its source code, documentation, and the codespec/codemodel context of its module.",
-					InputSchema: map[string]interface{}{
-						"type": "object",
-						"properties": map[string]interface{}{
-							"path": map[string]interface{}{
-								"type":        "string",
-								"description": "ABSOLUTE path to the module containing the symbol.",
-							},
-							"symbol": map[string]interface{}{
-								"type":        "string",
-								"description": "Name of the symbol (function, struct, etc.) to inspect.",
-							},
-						},
-						"required": []string{"path", "symbol"},
-					},
-				},
-			},
+			ProtocolVersion: "", // Newest version initialize may agree on; empty means the newest the server supports
+			// Tools are registered by the MCP adapter; entries here override a tool's
+			// description or input schema, or disable it, by tool name.
+			ToolDefinitions: map[string]ToolMetadata{},
 		},
 		Scaffold: ScaffoldConfig{
 			DefaultType:     "library",
@@ -356,6 +250,12 @@ Auto-generated by ASDP. Run asdp_sync to populate.
 				FirstSyncHash:  "PENDING_FIRST_SYNC",
 				HeaderTemplate: "\n# Semantic Model\n\nAuto-generated by ASDP Sync.\n",
 			},
+			Watch: WatchConfig{
+				Mode:         "sync",
+				Backend:      "auto",
+				Debounce:     500 * time.Millisecond,
+				PollInterval: 2 * time.Second,
+			},
 		},
 		Validation: ValidationConfig{
 			MandatoryFiles:   []string{"codetree.md"},
@@ -370,6 +270,9 @@ Auto-generated by ASDP. Run asdp_sync to populate.
 		System: SystemConfig{
 			GlobalAssetsDir: ".asdp/core/agent",
 			DefaultAgentDir: ".agent",
+			LogDir:          ".asdp/logs",
+			LogLevel:        "info",
+			CacheDir:        ".asdp/cache",
 		},
 	}
 }
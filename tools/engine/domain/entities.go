@@ -51,6 +51,11 @@ type Integrity struct {
 	Algorithm    string    `yaml:"algorithm"`
 	LastModified time.Time `yaml:"last_modified"`
 	CheckedAt    time.Time `yaml:"checked_at"`
+
+	// Per-file hashes (by slash-separated path relative to the module) behind SrcHash,
+	// and the version of the parser that produced the symbols; see sync.model.file_manifest
+	Files         map[string]string `yaml:"files,omitempty"`
+	ParserVersion string            `yaml:"parser_version,omitempty"`
 }
 
 type Symbol struct {
@@ -63,6 +68,22 @@ type Symbol struct {
 	Signature string `yaml:"signature" json:"signature"`
 	Docstring string `yaml:"docstring,omitempty" json:"docstring,omitempty"`
 	Parent    string `yaml:"parent,omitempty" json:"parent,omitempty"`
+
+	// Decorators of a Python or TypeScript declaration, without the "@" (e.g. "staticmethod")
+	Decorators []string `yaml:"decorators,omitempty,flow" json:"decorators,omitempty"`
+
+	// Type-checked facts (parsing.go.type_check): the import paths of a package symbol,
+	// the symbols of other packages a symbol refers to ("pkg.Name", "pkg.Type.Method")
+	// and the interfaces a type implements ("pkg.Interface", or "Interface" in its own package)
+	Imports    []string `yaml:"imports,omitempty" json:"imports,omitempty"`
+	References []string `yaml:"references,omitempty" json:"references,omitempty"`
+	Implements []string `yaml:"implements,omitempty" json:"implements,omitempty"`
+
+	// Static call graph (parsing.go.call_graph): the functions and methods of the same
+	// module this one calls, as "Name" or "Parent.Name" in its own package and as
+	// "dir:Name" or "dir:Parent.Name" in the package at dir (slash-separated, relative to
+	// the module); calls through an interface of the module name the interface method
+	Calls []string `yaml:"calls,omitempty,flow" json:"calls,omitempty"`
 }
 
 // --- CodeTree (Hierarchy) ---
@@ -104,10 +125,32 @@ type ValidationResult struct {
 }
 
 type Freshness struct {
-	Status      string `json:"status"` // "fresh", "stale", "unknown"
-	Reason      string `json:"reason,omitempty"`
-	CurrentHash string `json:"current_hash,omitempty"`
-	DocHash     string `json:"doc_hash,omitempty"`
+	Status      string           `json:"status"` // "fresh", "stale", "unknown"
+	Reason      string           `json:"reason,omitempty"`
+	CurrentHash string           `json:"current_hash,omitempty"`
+	DocHash     string           `json:"doc_hash,omitempty"`
+	Issues      []FreshnessIssue `json:"issues,omitempty"`
+}
+
+// Freshness issue codes. The first two make the codemodel stale; the others are reported
+// alongside a fresh or stale status.
+const (
+	FreshnessNeverSynced     = "never_synced"      // src_hash is still the first-sync placeholder
+	FreshnessHashDrift       = "hash_drift"        // The sources no longer hash to src_hash
+	FreshnessMtimeDrift      = "mtime_drift"       // Sources touched since the last sync, content unchanged
+	FreshnessSpecBehindModel = "spec_behind_model" // Sources changed after the codespec's last_modified
+)
+
+// FreshnessIssue explains one way a module's artifacts lag behind its sources.
+type FreshnessIssue struct {
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Files   []string `json:"files,omitempty"` // Relative to the module: the files that caused it, when known
+
+	// For hash_drift against a codemodel with a file manifest: Files, by kind of change
+	Added    []string `json:"added,omitempty"`
+	Removed  []string `json:"removed,omitempty"`
+	Modified []string `json:"modified,omitempty"`
 }
 
 // ContextResponse is the DTO for QueryContext
@@ -118,4 +161,21 @@ type ContextResponse struct {
 	Validation *ValidationResult `json:"validation,omitempty"`
 	Spec       CodeSpec          `json:"spec"`
 	Model      CodeModel         `json:"model"`
+	Children   []ContextResponse `json:"children,omitempty"` // Child modules, when queried with a depth
+	Budget     *ContextBudget    `json:"budget,omitempty"`   // Set on the root when queried with max_tokens
+}
+
+// ContextBudget reports how a token-budgeted context was packed.
+type ContextBudget struct {
+	MaxTokens  int            `json:"max_tokens"`
+	UsedTokens int            `json:"used_tokens"` // Estimated
+	Omitted    []OmittedItems `json:"omitted,omitempty"`
+}
+
+// OmittedItems records content left out of a budgeted context, so it can be asked for separately.
+type OmittedItems struct {
+	Path   string `json:"path"`            // Module the content belongs to
+	Item   string `json:"item"`            // e.g. "requirements", "docstrings", "spec_body", "children"
+	Count  int    `json:"count,omitempty"` // Number of entries, for per-symbol items
+	Tokens int    `json:"tokens"`          // Estimated tokens it would have taken
 }
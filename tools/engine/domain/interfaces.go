@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // FileSystem abstraction for testability
 type FileSystem interface {
@@ -20,11 +23,30 @@ type FileInfo interface {
 
 // Parser abstraction for AST operations
 type ASTParser interface {
-	ParseDir(root string) ([]Symbol, error)
+	ParseDir(ctx context.Context, root string) ([]Symbol, error)
 	GetSymbolBody(root string, symbol Symbol) (string, error)
 }
 
+// FileParser is implemented by ASTParsers that can parse a subset of a module's files,
+// letting a sync re-parse only the files that changed since the previous one.
+type FileParser interface {
+	// ParseFiles parses the given files (slash-separated, relative to root) of the module at root
+	ParseFiles(ctx context.Context, root string, files []string) ([]Symbol, error)
+	// ParserVersion changes whenever the symbols produced for the same file would differ.
+	// It is empty when they also depend on other files, which rules out partial parses.
+	ParserVersion() string
+}
+
 // Hasher abstraction for integrity checks
 type ContentHasher interface {
-	HashDir(path string) (string, error)
+	HashDir(ctx context.Context, path string) (string, error)
+	// ManifestDir also returns the hash of each file, by slash-separated path relative to path
+	ManifestDir(ctx context.Context, path string) (string, map[string]string, error)
+}
+
+// FileWatcher reports the paths that changed under root (files created, written,
+// removed or renamed) until ctx is done. Directories whose name skipDir accepts are
+// not watched.
+type FileWatcher interface {
+	Watch(ctx context.Context, root string, skipDir func(name string) bool, onChange func(paths []string)) error
 }
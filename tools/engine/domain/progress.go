@@ -0,0 +1,21 @@
+package domain
+
+import "context"
+
+// ProgressFunc receives incremental progress from long-running use cases.
+// Progress only ever increases; total is 0 when it is not known in advance.
+type ProgressFunc func(progress, total int, message string)
+
+type progressKey struct{}
+
+// WithProgress attaches a progress callback to ctx for the use cases it is passed to.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// ReportProgress forwards progress to the callback attached to ctx, if any.
+func ReportProgress(ctx context.Context, progress, total int, message string) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		fn(progress, total, message)
+	}
+}
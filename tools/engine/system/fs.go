@@ -1,6 +1,8 @@
 package system
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -77,3 +79,56 @@ func (fi *realFileInfo) IsDir() bool {
 func (fi *realFileInfo) ModTime() time.Time {
 	return fi.info.ModTime()
 }
+
+// isModuleBoundary reports whether dir holds a module of its own, whose sources belong to its codemodel.
+func isModuleBoundary(dir string) bool {
+	for _, artifact := range []string{"codespec.md", "codemodel.md"} {
+		if _, err := os.Stat(filepath.Join(dir, artifact)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// asdpArtifacts are the files CachedFileSystem keeps in its index: small, and read on almost every call.
+var asdpArtifacts = map[string]bool{"codespec.md": true, "codemodel.md": true, "codetree.md": true}
+
+// CachedFileSystem is a RealFileSystem that serves ASDP artifacts from a ProjectIndex
+// while their FileStamp is unchanged, so repeated queries cost a stat instead of a read.
+type CachedFileSystem struct {
+	RealFileSystem
+	index *ProjectIndex
+}
+
+func NewCachedFileSystem(index *ProjectIndex) *CachedFileSystem {
+	return &CachedFileSystem{index: index}
+}
+
+func (fs *CachedFileSystem) ReadFile(path string) ([]byte, error) {
+	if !asdpArtifacts[filepath.Base(path)] {
+		return fs.RealFileSystem.ReadFile(path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	stamp := stampOf(info)
+	if f, ok := fs.index.lookup(path, stamp); ok && f.Content != nil {
+		return append([]byte(nil), f.Content...), nil
+	}
+
+	checked := time.Now().UnixNano()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	fs.index.store(path, &IndexedFile{Stamp: stamp, Checked: checked, Hash: hex.EncodeToString(sum[:]), Content: append([]byte(nil), data...)})
+	return data, nil
+}
+
+func (fs *CachedFileSystem) WriteFile(path string, data []byte) error {
+	fs.index.forget(path)
+	return fs.RealFileSystem.WriteFile(path, data)
+}
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/Josepavese/asdp/engine/domain"
 )
@@ -16,6 +18,7 @@ import (
 type SHA256ContentHasher struct {
 	fs     *RealFileSystem
 	config domain.HasherConfig
+	index  *ProjectIndex // Optional
 }
 
 func NewSHA256ContentHasher(config domain.HasherConfig) *SHA256ContentHasher {
@@ -25,10 +28,29 @@ func NewSHA256ContentHasher(config domain.HasherConfig) *SHA256ContentHasher {
 	}
 }
 
+// NewIndexedContentHasher returns a hasher that reuses the hash of a directory while none
+// of its files changed, and records the hash of every file it reads in index.
+func NewIndexedContentHasher(config domain.HasherConfig, index *ProjectIndex) *SHA256ContentHasher {
+	h := NewSHA256ContentHasher(config)
+	h.index = index
+	return h
+}
+
 // HashDir calculates a deterministic hash of the semantic content of a directory (Non-Recursive).
 // It only considers regular files in the root folder, ignoring dependencies and hidden items.
-func (h *SHA256ContentHasher) HashDir(root string) (string, error) {
-	var files []string
+func (h *SHA256ContentHasher) HashDir(ctx context.Context, root string) (string, error) {
+	hash, _, err := h.ManifestDir(ctx, root)
+	return hash, err
+}
+
+// ManifestDir is HashDir that also returns the SHA-256 of every hashed file, by
+// slash-separated path relative to root.
+func (h *SHA256ContentHasher) ManifestDir(ctx context.Context, root string) (string, map[string]string, error) {
+	type hashedFile struct {
+		path  string
+		stamp FileStamp
+	}
+	var files []hashedFile
 
 	isIgnored := func(name string) bool {
 		name = strings.ToLower(name)
@@ -62,6 +84,9 @@ func (h *SHA256ContentHasher) HashDir(root string) (string, error) {
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
 		name := d.Name()
 		if d.IsDir() {
@@ -73,11 +98,7 @@ func (h *SHA256ContentHasher) HashDir(root string) (string, error) {
 				return filepath.SkipDir
 			}
 			// Boundary Check: If this directory is a separate ASDP module, skip it.
-			// We check for codespec.md or codemodel.md
-			if _, err := os.Stat(filepath.Join(path, "codespec.md")); err == nil {
-				return filepath.SkipDir
-			}
-			if _, err := os.Stat(filepath.Join(path, "codemodel.md")); err == nil {
+			if isModuleBoundary(path) {
 				return filepath.SkipDir
 			}
 			return nil
@@ -94,35 +115,64 @@ func (h *SHA256ContentHasher) HashDir(root string) (string, error) {
 			return nil
 		}
 
-		files = append(files, path)
+		files = append(files, hashedFile{path: path, stamp: stampOf(info)})
 		return nil
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to walk directory %s: %w", root, err)
+		return "", nil, fmt.Errorf("failed to walk directory %s: %w", root, err)
 	}
 
 	// 2. Sort files to ensure deterministic order
-	sort.Strings(files)
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	rels := make([]string, len(files))
+	stamps := make([]FileStamp, len(files))
+	for i, f := range files {
+		rels[i], _ = filepath.Rel(root, f.path)
+		stamps[i] = f.stamp
+	}
+	if h.index != nil {
+		if hash, ok := h.index.dirHash(root, rels, stamps); ok {
+			if manifest, ok := h.index.fileHashes(root, rels); ok {
+				return hash, manifest, nil
+			}
+		}
+	}
 
 	// 3. Hash content
+	checked := time.Now().UnixNano()
 	hasher := sha256.New()
-	for _, file := range files {
-		f, err := os.Open(file)
+	manifest := make(map[string]string, len(files))
+	complete := true
+	for i, file := range files {
+		if err := ctx.Err(); err != nil {
+			return "", nil, err
+		}
+		f, err := os.Open(file.path)
 		if err != nil {
+			complete = false
 			continue // Skip files we can't open after collection
 		}
 
 		// Hash filename first (to detect renames)
-		relPath, _ := filepath.Rel(root, file)
-		hasher.Write([]byte(relPath))
+		hasher.Write([]byte(rels[i]))
 
-		// Hash content
-		if _, err := io.Copy(hasher, f); err != nil {
+		// Hash content (and, for the index, the file alone)
+		fileHasher := sha256.New()
+		if _, err := io.Copy(io.MultiWriter(hasher, fileHasher), f); err != nil {
 			f.Close()
-			return "", err
+			return "", nil, err
 		}
 		f.Close()
+		fileHash := hex.EncodeToString(fileHasher.Sum(nil))
+		manifest[filepath.ToSlash(rels[i])] = fileHash
+		if h.index != nil {
+			h.index.store(file.path, &IndexedFile{Stamp: file.stamp, Checked: checked, Hash: fileHash})
+		}
 	}
 
-	return hex.EncodeToString(hasher.Sum(nil)), nil
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	if h.index != nil && complete {
+		h.index.storeDirHash(root, rels, sum)
+	}
+	return sum, manifest, nil
 }
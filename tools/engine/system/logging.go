@@ -0,0 +1,35 @@
+package system
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// OpenLogFile opens (appending) <LogDir>/<name>.log. A relative LogDir is resolved against the home directory.
+func OpenLogFile(config domain.SystemConfig, name string) (*os.File, error) {
+	dir := config.LogDir
+	if !filepath.IsAbs(dir) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dir = filepath.Join(home, dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log dir: %w", err)
+	}
+	return os.OpenFile(filepath.Join(dir, name+".log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+}
+
+// ParseLogLevel maps a config level name (debug, info, warn, error) to a slog.Level, defaulting to info.
+func ParseLogLevel(name string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
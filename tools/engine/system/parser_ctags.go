@@ -3,8 +3,10 @@ package system
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -58,7 +60,7 @@ func (p *CtagsParser) GetSymbolBody(root string, sym domain.Symbol) (string, err
 	return strings.Join(lines[sym.Line-1:end], "\n"), nil
 }
 
-func (p *CtagsParser) ParseDir(root string) ([]domain.Symbol, error) {
+func (p *CtagsParser) ParseDir(ctx context.Context, root string) ([]domain.Symbol, error) {
 	// Check if ctags is available
 	if _, err := exec.LookPath(p.config.Parsing.Ctags.Binary); err != nil {
 		if p.config.Parsing.Ctags.AllowMissing {
@@ -84,6 +86,9 @@ func (p *CtagsParser) ParseDir(root string) ([]domain.Symbol, error) {
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if d.IsDir() {
 			if path == root {
 				return nil
@@ -92,17 +97,13 @@ func (p *CtagsParser) ParseDir(root string) ([]domain.Symbol, error) {
 				return filepath.SkipDir
 			}
 			// Boundary Check
-			if _, err := os.Stat(filepath.Join(path, "codespec.md")); err == nil {
-				return filepath.SkipDir
-			}
-			if _, err := os.Stat(filepath.Join(path, "codemodel.md")); err == nil {
+			if isModuleBoundary(path) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		// Skip Go files (handled by GoASTParser) and others
-		if strings.HasSuffix(d.Name(), ".go") || strings.HasSuffix(d.Name(), ".md") {
+		if p.skips(d.Name()) {
 			return nil
 		}
 
@@ -116,6 +117,52 @@ func (p *CtagsParser) ParseDir(root string) ([]domain.Symbol, error) {
 		return nil, fmt.Errorf("failed to walk dir: %w", err)
 	}
 
+	return p.scanFiles(ctx, root, filesToScan)
+}
+
+// skips reports whether name is left to another parser: Go files to GoASTParser, the
+// extensions of the enabled built-in parsers to them, and Markdown to no one.
+func (p *CtagsParser) skips(name string) bool {
+	if strings.HasSuffix(name, ".go") || strings.HasSuffix(name, ".md") {
+		return true
+	}
+	native := p.config.Parsing
+	return (native.Python.Enabled && hasExtension(name, native.Python.Extensions)) ||
+		(native.JavaScript.Enabled && hasExtension(name, native.JavaScript.Extensions))
+}
+
+// ctagsParserVersion changes whenever scanFiles would produce different symbols for the same file.
+const ctagsParserVersion = "ctags/1"
+
+func (p *CtagsParser) ParserVersion() string {
+	return ctagsParserVersion
+}
+
+// ParseFiles runs ctags on the given files (slash-separated, relative to root), skipping
+// the ones ParseDir would skip. Files that no longer exist are ignored.
+func (p *CtagsParser) ParseFiles(ctx context.Context, root string, files []string) ([]domain.Symbol, error) {
+	if _, err := exec.LookPath(p.config.Parsing.Ctags.Binary); err != nil {
+		if p.config.Parsing.Ctags.AllowMissing {
+			return []domain.Symbol{}, nil
+		}
+		return nil, fmt.Errorf("ctags binary not found: %w", err)
+	}
+
+	var filesToScan []string
+	for _, rel := range files {
+		if p.skips(rel) || inIgnoredDir(p.config.IgnorePatterns, rel) {
+			continue
+		}
+		full := filepath.Join(root, filepath.FromSlash(rel))
+		if info, err := os.Stat(full); err == nil && info.Mode().IsRegular() {
+			filesToScan = append(filesToScan, full)
+		}
+	}
+	return p.scanFiles(ctx, root, filesToScan)
+}
+
+// scanFiles runs ctags on files (absolute paths) and maps its output to symbols.
+func (p *CtagsParser) scanFiles(ctx context.Context, root string, filesToScan []string) ([]domain.Symbol, error) {
 	if len(filesToScan) == 0 {
 		return []domain.Symbol{}, nil
 	}
@@ -127,7 +174,7 @@ func (p *CtagsParser) ParseDir(root string) ([]domain.Symbol, error) {
 	}
 	args = append(args, "-L", "-")
 
-	cmd := exec.Command(p.config.Parsing.Ctags.Binary, args...)
+	cmd := exec.CommandContext(ctx, p.config.Parsing.Ctags.Binary, args...)
 
 	var out bytes.Buffer
 	cmd.Stdout = &out
@@ -151,6 +198,9 @@ func (p *CtagsParser) ParseDir(root string) ([]domain.Symbol, error) {
 	}()
 
 	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		// Verify if it's an exit code issue or IO
 		// ctags might exit with non-zero if warnings?
 		// For now simple error return
@@ -163,7 +213,8 @@ func (p *CtagsParser) ParseDir(root string) ([]domain.Symbol, error) {
 		line := scanner.Bytes()
 		var entry CtagsEntry
 		if err := json.Unmarshal(line, &entry); err != nil {
-			continue // skip malformed lines
+			slog.DebugContext(ctx, "skipping malformed ctags output line", "root", root, "error", err)
+			continue
 		}
 
 		// Filter out "ptag" or other internal metadata if needed.
@@ -1,13 +1,21 @@
 package system
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"go/ast"
 	"go/parser"
+	"go/printer"
 	"go/token"
+	"go/types"
+	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/Josepavese/asdp/engine/domain"
 )
@@ -15,6 +23,9 @@ import (
 type GoASTParser struct {
 	fs     *RealFileSystem
 	config domain.Config
+
+	stdMu sync.Mutex
+	std   types.Importer // Standard library, type-checked from GOROOT once per parser
 }
 
 func NewGoASTParser(config domain.Config) *GoASTParser {
@@ -53,7 +64,7 @@ func (p *GoASTParser) GetSymbolBody(root string, sym domain.Symbol) (string, err
 	return strings.Join(lines[sym.Line-1:end], "\n"), nil
 }
 
-func (p *GoASTParser) ParseDir(root string) ([]domain.Symbol, error) {
+func (p *GoASTParser) ParseDir(ctx context.Context, root string) ([]domain.Symbol, error) {
 	var symbols []domain.Symbol
 	fset := token.NewFileSet()
 
@@ -72,6 +83,9 @@ func (p *GoASTParser) ParseDir(root string) ([]domain.Symbol, error) {
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if d.IsDir() {
 			if path == root {
 				return nil
@@ -80,10 +94,7 @@ func (p *GoASTParser) ParseDir(root string) ([]domain.Symbol, error) {
 				return filepath.SkipDir
 			}
 			// Boundary Check
-			if _, err := os.Stat(filepath.Join(path, "codespec.md")); err == nil {
-				return filepath.SkipDir
-			}
-			if _, err := os.Stat(filepath.Join(path, "codemodel.md")); err == nil {
+			if isModuleBoundary(path) {
 				return filepath.SkipDir
 			}
 			return nil
@@ -97,93 +108,410 @@ func (p *GoASTParser) ParseDir(root string) ([]domain.Symbol, error) {
 			return nil
 		}
 
-		// Parse individual file
-		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
-		if err != nil {
-			// Log error but continue? Or fail?
-			// For robustness, let's log and continue
-			return nil
+		symbols = append(symbols, p.parseFile(ctx, fset, root, path)...)
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk go directory %s: %w", root, err)
+	}
+
+	return p.annotate(ctx, root, symbols), nil
+}
+
+// goParserVersion changes whenever parseFile would produce different symbols for the same file.
+const goParserVersion = "go/9"
+
+func (p *GoASTParser) ParserVersion() string {
+	if p.config.Parsing.Go.TypeCheck {
+		return "" // Type facts of a file depend on the rest of its package, and on its imports
+	}
+	version := goParserVersion
+	if !p.config.Parsing.Go.Fields {
+		version += "-nofields" // The same files yield no struct fields
+	}
+	if !p.config.Parsing.Go.CallGraph {
+		version += "-nocalls"
+	}
+	return version
+}
+
+// ParseFiles parses the given Go files (slash-separated, relative to root), skipping the
+// ones ParseDir would skip. Files that no longer exist are ignored.
+func (p *GoASTParser) ParseFiles(ctx context.Context, root string, files []string) ([]domain.Symbol, error) {
+	var symbols []domain.Symbol
+	fset := token.NewFileSet()
+	for _, rel := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		name := path.Base(rel)
+		if !strings.HasSuffix(name, ".go") || (p.config.Parsing.Go.SkipTests && strings.HasSuffix(name, "_test.go")) {
+			continue
+		}
+		if inIgnoredDir(p.config.IgnorePatterns, rel) {
+			continue
+		}
+		full := filepath.Join(root, filepath.FromSlash(rel))
+		if _, err := os.Stat(full); err != nil {
+			continue
 		}
+		symbols = append(symbols, p.parseFile(ctx, fset, root, full)...)
+	}
+	return p.annotate(ctx, root, symbols), nil
+}
+
+// inIgnoredDir reports whether a directory of rel (slash-separated) matches one of the
+// ignore patterns ParseDir skips directories by.
+func inIgnoredDir(patterns []string, rel string) bool {
+	for _, dir := range strings.Split(path.Dir(rel), "/") {
+		if dir != "." && matchesIgnorePattern(patterns, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesIgnorePattern reports whether a file or directory name contains one of the ignore patterns.
+func matchesIgnorePattern(patterns []string, name string) bool {
+	name = strings.ToLower(name)
+	for _, p := range patterns {
+		if strings.Contains(name, p) {
+			return true
+		}
+	}
+	return false
+}
 
-		// Extract symbols from file
-		for _, decl := range f.Decls {
-			// 1. Functions
-			if fn, ok := decl.(*ast.FuncDecl); ok {
-				pos := fset.Position(fn.Pos())
-				sym := domain.Symbol{
-					Name:      fn.Name.Name,
-					Kind:      "function",
-					Exported:  fn.Name.IsExported(),
-					Line:      pos.Line,
-					LineEnd:   fset.Position(fn.End()).Line,
-					Docstring: strings.TrimSpace(fn.Doc.Text()),
-					Signature: formatFuncSignature(fn),
+// parseFile extracts the symbols of one Go file. A file that fails to parse yields none.
+func (p *GoASTParser) parseFile(ctx context.Context, fset *token.FileSet, root, path string) []domain.Symbol {
+	var symbols []domain.Symbol
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		// For robustness, log and continue: one broken file must not empty the whole model
+		slog.WarnContext(ctx, "skipping Go file that failed to parse", "file", path, "error", err)
+		return nil
+	}
+
+	// Extract symbols from file
+	for _, decl := range f.Decls {
+		// 1. Functions
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			pos := fset.Position(fn.Pos())
+			sym := domain.Symbol{
+				Name:      fn.Name.Name,
+				Kind:      "function",
+				Exported:  fn.Name.IsExported(),
+				Line:      pos.Line,
+				LineEnd:   fset.Position(fn.End()).Line,
+				Docstring: strings.TrimSpace(fn.Doc.Text()),
+				Signature: formatFuncSignature(fset, fn),
+			}
+			// Check if it's a method
+			if fn.Recv != nil {
+				sym.Kind = "method"
+				for _, field := range fn.Recv.List {
+					sym.Parent = receiverTypeName(field.Type)
 				}
-				// Check if it's a method
-				if fn.Recv != nil {
-					sym.Kind = "method"
-					for _, field := range fn.Recv.List {
-						if star, ok := field.Type.(*ast.StarExpr); ok {
-							if ident, ok := star.X.(*ast.Ident); ok {
-								sym.Parent = ident.Name
-							}
-						} else if ident, ok := field.Type.(*ast.Ident); ok {
-							sym.Parent = ident.Name
+			}
+			relPath, _ := filepath.Rel(root, path)
+			sym.FilePath = relPath
+			symbols = append(symbols, sym)
+		}
+
+		// 2. Types (Structs/Interfaces)
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+			for _, spec := range gen.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+					pos := fset.Position(typeSpec.Pos())
+					sym := domain.Symbol{
+						Name:      typeSpec.Name.Name,
+						Exported:  typeSpec.Name.IsExported(),
+						Line:      pos.Line,
+						LineEnd:   fset.Position(typeSpec.End()).Line,
+						Docstring: strings.TrimSpace(gen.Doc.Text()),
+						Signature: formatTypeSignature(fset, typeSpec),
+					}
+
+					relPath, _ := filepath.Rel(root, path)
+					sym.FilePath = relPath
+
+					var members []domain.Symbol
+					switch t := typeSpec.Type.(type) {
+					case *ast.StructType:
+						sym.Kind = "struct"
+						if p.config.Parsing.Go.Fields {
+							members = structFields(fset, typeSpec.Name.Name, t)
+						}
+					case *ast.InterfaceType:
+						sym.Kind = "interface"
+						members = interfaceMethods(fset, typeSpec.Name.Name, t)
+					default:
+						sym.Kind = "type"
+						if typeSpec.Assign.IsValid() {
+							sym.Kind = "alias"
 						}
 					}
+					symbols = append(symbols, sym)
+					for _, m := range members {
+						m.FilePath = relPath
+						symbols = append(symbols, m)
+					}
 				}
-				relPath, _ := filepath.Rel(root, path)
-				sym.FilePath = relPath
-				symbols = append(symbols, sym)
 			}
+		}
 
-			// 2. Types (Structs/Interfaces)
-			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
-				for _, spec := range gen.Specs {
-					if typeSpec, ok := spec.(*ast.TypeSpec); ok {
-						pos := fset.Position(typeSpec.Pos())
-						sym := domain.Symbol{
-							Name:      typeSpec.Name.Name,
-							Exported:  typeSpec.Name.IsExported(),
-							Line:      pos.Line,
-							LineEnd:   fset.Position(typeSpec.End()).Line,
-							Docstring: strings.TrimSpace(gen.Doc.Text()),
-							Signature: fmt.Sprintf("type %s", typeSpec.Name.Name),
-						}
-
-						switch typeSpec.Type.(type) {
-						case *ast.StructType:
-							sym.Kind = "struct"
-						case *ast.InterfaceType:
-							sym.Kind = "interface"
-						default:
-							sym.Kind = "type"
-						}
-						relPath, _ := filepath.Rel(root, path)
-						sym.FilePath = relPath
-						symbols = append(symbols, sym)
+		// 3. Package-level constants and variables
+		if gen, ok := decl.(*ast.GenDecl); ok && (gen.Tok == token.CONST || gen.Tok == token.VAR) {
+			relPath, _ := filepath.Rel(root, path)
+			for _, spec := range gen.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				doc := valueSpec.Doc
+				if doc == nil && len(gen.Specs) == 1 {
+					doc = gen.Doc
+				}
+				for i, name := range valueSpec.Names {
+					if name.Name == "_" {
+						continue
 					}
+					symbols = append(symbols, domain.Symbol{
+						Name:      name.Name,
+						Kind:      gen.Tok.String(),
+						Exported:  name.IsExported(),
+						Line:      fset.Position(name.Pos()).Line,
+						LineEnd:   fset.Position(valueSpec.End()).Line,
+						FilePath:  relPath,
+						Docstring: commentText(doc, valueSpec.Comment),
+						Signature: formatValueSignature(fset, gen.Tok, valueSpec, i),
+					})
 				}
 			}
 		}
+	}
+	return symbols
+}
 
-		return nil
-	})
+// structFields returns the fields of the struct typeName as its child symbols, embedded
+// fields named after their type. FilePath is left to the caller.
+func structFields(fset *token.FileSet, typeName string, st *ast.StructType) []domain.Symbol {
+	var fields []domain.Symbol
+	for _, field := range st.Fields.List {
+		typ := printNode(fset, field.Type)
+		tag := ""
+		if field.Tag != nil {
+			tag = " " + field.Tag.Value
+		}
+		base := domain.Symbol{
+			Kind:      "field",
+			Parent:    typeName,
+			LineEnd:   fset.Position(field.End()).Line,
+			Docstring: commentText(field.Doc, field.Comment),
+		}
+		if len(field.Names) == 0 {
+			sym := base
+			sym.Name = receiverTypeName(field.Type)
+			if sym.Name == "" {
+				sym.Name = typ
+			}
+			sym.Exported = ast.IsExported(sym.Name)
+			sym.Line = fset.Position(field.Pos()).Line
+			sym.Signature = typ + tag
+			fields = append(fields, sym)
+			continue
+		}
+		for _, name := range field.Names {
+			sym := base
+			sym.Name = name.Name
+			sym.Exported = name.IsExported()
+			sym.Line = fset.Position(name.Pos()).Line
+			sym.Signature = name.Name + " " + typ + tag
+			fields = append(fields, sym)
+		}
+	}
+	return fields
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to walk go directory %s: %w", root, err)
+// interfaceMethods returns the method set declared by the interface typeName as its child
+// symbols: methods, and embedded interfaces or type-set terms (kind "embedded").
+func interfaceMethods(fset *token.FileSet, typeName string, it *ast.InterfaceType) []domain.Symbol {
+	var methods []domain.Symbol
+	for _, field := range it.Methods.List {
+		sym := domain.Symbol{
+			Parent:    typeName,
+			Line:      fset.Position(field.Pos()).Line,
+			LineEnd:   fset.Position(field.End()).Line,
+			Docstring: commentText(field.Doc, field.Comment),
+		}
+		if fn, ok := field.Type.(*ast.FuncType); ok && len(field.Names) > 0 {
+			sym.Name = field.Names[0].Name
+			sym.Kind = "method"
+			sym.Exported = field.Names[0].IsExported()
+			sym.Signature = sym.Name + strings.TrimPrefix(printNode(fset, fn), "func")
+		} else {
+			sym.Signature = printNode(fset, field.Type)
+			sym.Name = receiverTypeName(field.Type)
+			if sym.Name == "" {
+				sym.Name = sym.Signature // A type-set term such as ~int | ~string
+			}
+			sym.Kind = "embedded"
+			sym.Exported = ast.IsExported(sym.Name)
+		}
+		methods = append(methods, sym)
 	}
+	return methods
+}
+
+// maxValueLen bounds the initializer shown in a const or var signature.
+const maxValueLen = 80
 
-	return symbols, nil
+// capValue cuts an initializer shown in a signature to maxValueLen runes.
+func capValue(value string) string {
+	if r := []rune(value); len(r) > maxValueLen {
+		return string(r[:maxValueLen]) + "..."
+	}
+	return value
 }
 
-// Helper to reconstruct signature string roughly
-func formatFuncSignature(fn *ast.FuncDecl) string {
-	// Ideally we use printer.Fprint, but simple reconstruction is fine for now
-	sig := "func "
-	if fn.Recv != nil {
-		sig += "(...)" // Method
+// formatValueSignature renders the i-th name of a const or var spec, e.g.
+// "const Version = \"1.0\"" or "var ErrNotFound error"; long initializers are cut.
+func formatValueSignature(fset *token.FileSet, tok token.Token, spec *ast.ValueSpec, i int) string {
+	sig := tok.String() + " " + spec.Names[i].Name
+	if spec.Type != nil {
+		sig += " " + printNode(fset, spec.Type)
+	}
+	if i < len(spec.Values) {
+		sig += " = " + capValue(printNode(fset, spec.Values[i]))
 	}
-	sig += fn.Name.Name + "(...)"
 	return sig
 }
+
+// commentText returns the doc comment of a declaration, or else its trailing line comment.
+func commentText(doc, line *ast.CommentGroup) string {
+	if text := strings.TrimSpace(doc.Text()); text != "" {
+		return text
+	}
+	return strings.TrimSpace(line.Text())
+}
+
+// formatFuncSignature renders the declaration of fn without its body, e.g.
+// "func (l *List[T]) Push(v T) error", on one line.
+func formatFuncSignature(fset *token.FileSet, fn *ast.FuncDecl) string {
+	decl := *fn
+	decl.Doc, decl.Body = nil, nil
+	return printNode(fset, &decl)
+}
+
+// formatTypeSignature renders a type declaration, e.g. "type ID string" or
+// "type Pair[K comparable, V any] = map[K]V". Struct and interface bodies are left out
+// ("type List[T any] struct"): their members are symbols of their own.
+func formatTypeSignature(fset *token.FileSet, spec *ast.TypeSpec) string {
+	sig := "type " + spec.Name.Name
+	if spec.TypeParams != nil {
+		var params []string
+		for _, field := range spec.TypeParams.List {
+			names := make([]string, len(field.Names))
+			for i, name := range field.Names {
+				names[i] = name.Name
+			}
+			params = append(params, strings.Join(names, ", ")+" "+printNode(fset, field.Type))
+		}
+		sig += "[" + strings.Join(params, ", ") + "]"
+	}
+	switch spec.Type.(type) {
+	case *ast.StructType:
+		return sig + " struct"
+	case *ast.InterfaceType:
+		return sig + " interface"
+	}
+	if spec.Assign.IsValid() {
+		sig += " ="
+	}
+	return sig + " " + printNode(fset, spec.Type)
+}
+
+// receiverTypeName returns the type a method is declared on, for value, pointer and
+// generic receivers alike: T, *T, T[K], *T[K, V]. For an embedded field's type it is
+// the field name (pkg.T embeds T).
+func receiverTypeName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.SelectorExpr:
+			return e.Sel.Name
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+var (
+	signatureSpace = regexp.MustCompile(`\s*\n\s*`)
+	signatureTidy  = strings.NewReplacer(", )", ")", ", ]", "]", ", }", "}", ",)", ")", ",]", "]", ",}", "}", "( ", "(", "[ ", "[")
+)
+
+// printNode prints node as go/printer formats it, folded onto one line.
+func printNode(fset *token.FileSet, node any) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+	return foldSignature(buf.String(), true)
+}
+
+// foldSignature folds a multi-line declaration onto one line, dropping the trailing
+// commas and bracket padding line breaks leave behind, on both sides of the brackets. Quoted literals are copied as
+// written; rawBackquote tells a Go raw string (no escapes) from a JavaScript template.
+func foldSignature(sig string, rawBackquote bool) string {
+	sig = strings.TrimSpace(sig)
+	var sb strings.Builder
+	fold := func(code string) {
+		// A break right inside a bracket leaves no space, so "{\n\t1,\n}" becomes "{1}"
+		code = signatureSpace.ReplaceAllString(code, "\n")
+		var folded strings.Builder
+		for i := 0; i < len(code); i++ {
+			if code[i] != '\n' {
+				folded.WriteByte(code[i])
+				continue
+			}
+			opens := i > 0 && strings.IndexByte("([{", code[i-1]) >= 0
+			closes := i+1 < len(code) && strings.IndexByte(")]}", code[i+1]) >= 0
+			if !opens && !closes {
+				folded.WriteByte(' ')
+			}
+		}
+		sb.WriteString(signatureTidy.Replace(folded.String()))
+	}
+	start := 0
+	for i := 0; i < len(sig); i++ {
+		quote := sig[i]
+		if quote != '"' && quote != '\'' && quote != '`' {
+			continue
+		}
+		fold(sig[start:i])
+		end := len(sig)
+		for j := i + 1; j < len(sig); j++ {
+			if sig[j] == '\\' && (quote != '`' || !rawBackquote) {
+				j++
+			} else if sig[j] == quote {
+				end = j + 1
+				break
+			}
+		}
+		sb.WriteString(sig[i:end])
+		start, i = end, end-1
+	}
+	fold(sig[start:])
+	return sb.String()
+}
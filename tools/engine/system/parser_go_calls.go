@@ -0,0 +1,161 @@
+package system
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// annotateCalls records on each function and method the functions and methods of the
+// module it calls, resolved by type: direct calls, method calls on concrete receivers and
+// calls through interfaces declared in the module. Expanding the latter to the
+// implementers is left to the reader of the graph, so the calls of a file only change
+// with the file itself, and an incremental sync records the same calls as a full one.
+func (p *GoASTParser) annotateCalls(ctx context.Context, root string, symbols []domain.Symbol, imp *localImporter) {
+	callers := make(map[symbolKey]bool)
+	for _, sym := range symbols {
+		if sym.Kind == "function" || sym.Kind == "method" {
+			callers[symbolKey{file: sym.FilePath, parent: sym.Parent, name: sym.Name, line: sym.Line}] = true
+		}
+	}
+
+	calls := make(map[symbolKey][]string)
+	inModule := p.moduleFileFilter(root)
+	for _, dir := range symbolDirs(symbols) {
+		if ctx.Err() != nil {
+			return
+		}
+		cp, err := imp.checkDir(filepath.Join(root, dir))
+		if err != nil {
+			slog.WarnContext(ctx, "skipping Go package that failed to load", "dir", filepath.Join(root, dir), "error", err)
+			continue
+		}
+		for _, f := range cp.files {
+			for _, decl := range f.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Body == nil {
+					continue
+				}
+				parent := ""
+				if fn.Recv != nil && len(fn.Recv.List) > 0 {
+					parent = receiverTypeName(fn.Recv.List[0].Type)
+				}
+				caller := cp.keyOf(root, parent, fn.Name.Name, fn)
+				if callers[caller] {
+					calls[caller] = cp.callees(root, fn.Body, inModule)
+				}
+			}
+		}
+	}
+
+	for i := range symbols {
+		key := symbolKey{file: symbols[i].FilePath, parent: symbols[i].Parent, name: symbols[i].Name, line: symbols[i].Line}
+		if c, ok := calls[key]; ok {
+			symbols[i].Calls = c
+		}
+	}
+}
+
+// moduleFileFilter returns whether a file (relative to root) is one ParseDir parses:
+// a Go file below root, outside ignored directories and nested modules.
+func (p *GoASTParser) moduleFileFilter(root string) func(rel string) bool {
+	boundary := make(map[string]bool) // By directory relative to root
+	var crossesBoundary func(dir string) bool
+	crossesBoundary = func(dir string) bool {
+		if dir == "." {
+			return false
+		}
+		b, ok := boundary[dir]
+		if !ok {
+			b = isModuleBoundary(filepath.Join(root, dir)) || crossesBoundary(filepath.Dir(dir))
+			boundary[dir] = b
+		}
+		return b
+	}
+	return func(rel string) bool {
+		slashed := filepath.ToSlash(rel)
+		if rel == "" || strings.HasPrefix(slashed, "../") || !strings.HasSuffix(rel, ".go") {
+			return false
+		}
+		if p.config.Parsing.Go.SkipTests && strings.HasSuffix(rel, "_test.go") {
+			return false
+		}
+		return !inIgnoredDir(p.config.IgnorePatterns, slashed) && !crossesBoundary(filepath.Dir(rel))
+	}
+}
+
+// callees returns the module's functions and methods called within body, sorted: "Name"
+// or "Parent.Name" in the package of body, "dir:Name" or "dir:Parent.Name" in the
+// package at dir (slash-separated, relative to root, "." for the root package). Functions
+// belong to the module by where they are declared rather than by the symbols at hand, which
+// an incremental sync limits to the files that changed.
+func (cp *checkedPackage) callees(root string, body *ast.BlockStmt, inModule func(rel string) bool) []string {
+	seen := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn := calledFunc(cp.info, call)
+		if fn == nil || fn.Pkg() == nil {
+			return true
+		}
+		rel := cp.keyOf(root, "", fn.Name(), fn).file
+		if !inModule(rel) {
+			return true
+		}
+		name := fn.Name()
+		if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+			parent := namedTypeName(recv.Type())
+			if parent == "" {
+				return true // A method of an unnamed interface
+			}
+			name = parent + "." + name
+		}
+		if fn.Pkg() != cp.pkg {
+			name = filepath.ToSlash(filepath.Dir(rel)) + ":" + name
+		}
+		seen[name] = true
+		return true
+	})
+	return sortedKeys(seen)
+}
+
+// keyOf returns the symbolKey of a declaration of the package, or of an object anywhere.
+func (cp *checkedPackage) keyOf(root, parent, name string, at interface{ Pos() token.Pos }) symbolKey {
+	pos := cp.fset.Position(at.Pos())
+	rel, _ := filepath.Rel(root, pos.Filename)
+	return symbolKey{file: rel, parent: parent, name: name, line: pos.Line}
+}
+
+// calledFunc returns the function or method a call statically refers to, generic ones
+// by their declaration. Calls of function values yield nil.
+func calledFunc(info *types.Info, call *ast.CallExpr) *types.Func {
+	fun := ast.Unparen(call.Fun)
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+	var id *ast.Ident
+	switch f := fun.(type) {
+	case *ast.Ident:
+		id = f
+	case *ast.SelectorExpr:
+		id = f.Sel
+	default:
+		return nil
+	}
+	fn, ok := info.Uses[id].(*types.Func)
+	if !ok {
+		return nil
+	}
+	return fn.Origin()
+}
@@ -0,0 +1,34 @@
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+func TestCallsThroughInterfaceWithDefaultConfig(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.22\n",
+		"m.go":   "package m\n\ntype Store interface{ Get() string }\n\nfunc Use(s Store) { s.Get(); helper() }\n\nfunc helper() {}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := *domain.DefaultConfig()
+	config.Parsing.Go.CallGraph = true
+	symbols, err := NewGoASTParser(config).ParseDir(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := findSymbol(t, symbols, "", "Use").Calls; !reflect.DeepEqual(got, []string{"Store.Get", "helper"}) {
+		t.Errorf("Use calls %v, want [Store.Get helper]", got)
+	}
+}
@@ -0,0 +1,245 @@
+package system
+
+import (
+	"context"
+	"go/parser"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+func TestFoldSignature(t *testing.T) {
+	tests := []struct {
+		name         string
+		sig          string
+		rawBackquote bool
+		want         string
+	}{
+		{"trailing comma", "func f(\n\ta int,\n\tb string,\n)", true, "func f(a int, b string)"},
+		{"composite literal", "X = T{\n\t1,\n\t{2},\n}", true, "X = T{1, {2}}"},
+		{"one-line braces kept", "F = func() { return }", true, "F = func() { return }"},
+		{"string literal", `X = "( a, )"`, true, `X = "( a, )"`},
+		{"escaped quote", `X = "\"( a" + f( b)`, true, `X = "\"( a" + f(b)`},
+		{"rune literal", "X = '(' + ( 1)", true, "X = '(' + (1)"},
+		{"raw string with newlines", "X = `line ( 1\n  line 2\\` + f( b)", true, "X = `line ( 1\n  line 2\\` + f(b)"},
+		{"template with escaped backquote", "x = `a \\` ( b` + f( c)", false, "x = `a \\` ( b` + f(c)"},
+		{"unterminated literal", `x = f( "( a`, false, `x = f("( a`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := foldSignature(tt.sig, tt.rawBackquote); got != tt.want {
+				t.Errorf("foldSignature(%q) = %q, want %q", tt.sig, got, tt.want)
+			}
+		})
+	}
+}
+
+// parseGoSource parses src as the only file of a module, m.go.
+func parseGoSource(t *testing.T, config domain.Config, src string) []domain.Symbol {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "m.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	symbols, err := NewGoASTParser(config).ParseDir(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return symbols
+}
+
+const goDeclarations = `package m
+
+import "io"
+
+// Config is read from YAML.
+type Config struct {
+	*Base
+	io.Reader
+	List[int]
+	Name, Alias string ` + "`yaml:\"name\"`" + ` // Display name
+	level       int
+}
+
+type Store interface {
+	io.Closer
+	// Get reads key.
+	Get(key string) (string, error)
+	~int | ~string
+}
+
+type ID = string
+
+type Level int
+`
+
+func TestParseGoDeclarations(t *testing.T) {
+	config := *domain.DefaultConfig()
+	config.Parsing.Go.Fields = true
+	symbols := parseGoSource(t, config, goDeclarations)
+
+	want := []symbolLine{
+		{Name: "Config", Kind: "struct", Line: 6, LineEnd: 12, Exported: true},
+		{Name: "Base", Kind: "field", Parent: "Config", Line: 7, LineEnd: 7, Exported: true},
+		{Name: "Reader", Kind: "field", Parent: "Config", Line: 8, LineEnd: 8, Exported: true},
+		{Name: "List", Kind: "field", Parent: "Config", Line: 9, LineEnd: 9, Exported: true},
+		{Name: "Name", Kind: "field", Parent: "Config", Line: 10, LineEnd: 10, Exported: true},
+		{Name: "Alias", Kind: "field", Parent: "Config", Line: 10, LineEnd: 10, Exported: true},
+		{Name: "level", Kind: "field", Parent: "Config", Line: 11, LineEnd: 11},
+		{Name: "Store", Kind: "interface", Line: 14, LineEnd: 19, Exported: true},
+		{Name: "Closer", Kind: "embedded", Parent: "Store", Line: 15, LineEnd: 15, Exported: true},
+		{Name: "Get", Kind: "method", Parent: "Store", Line: 17, LineEnd: 17, Exported: true},
+		{Name: "~int | ~string", Kind: "embedded", Parent: "Store", Line: 18, LineEnd: 18},
+		{Name: "ID", Kind: "alias", Line: 21, LineEnd: 21, Exported: true},
+		{Name: "Level", Kind: "type", Line: 23, LineEnd: 23, Exported: true},
+	}
+	if got := symbolLines(symbols); !reflect.DeepEqual(got, want) {
+		t.Errorf("symbols:\n%+v\nwant\n%+v", got, want)
+	}
+
+	tests := []struct {
+		parent, name   string
+		signature, doc string
+	}{
+		{"", "Config", "type Config struct", "Config is read from YAML."},
+		{"Config", "Base", "*Base", ""},
+		{"Config", "Reader", "io.Reader", ""},
+		{"Config", "List", "List[int]", ""},
+		{"Config", "Name", "Name string `yaml:\"name\"`", "Display name"},
+		{"Config", "Alias", "Alias string `yaml:\"name\"`", "Display name"},
+		{"Store", "Closer", "io.Closer", ""},
+		{"Store", "Get", "Get(key string) (string, error)", "Get reads key."},
+		{"", "ID", "type ID = string", ""},
+		{"", "Level", "type Level int", ""},
+	}
+	for _, tt := range tests {
+		sym := findSymbol(t, symbols, tt.parent, tt.name)
+		if sym.Signature != tt.signature {
+			t.Errorf("%s signature = %q, want %q", tt.name, sym.Signature, tt.signature)
+		}
+		if sym.Docstring != tt.doc {
+			t.Errorf("%s docstring = %q, want %q", tt.name, sym.Docstring, tt.doc)
+		}
+	}
+}
+
+func TestParseGoWithoutFields(t *testing.T) {
+	config := *domain.DefaultConfig()
+	config.Parsing.Go.Fields = false
+	var got []string
+	for _, sym := range parseGoSource(t, config, goDeclarations) {
+		got = append(got, sym.Parent+"."+sym.Name)
+	}
+	// Interface method sets are kept: only struct fields depend on the switch
+	want := []string{".Config", ".Store", "Store.Closer", "Store.Get", "Store.~int | ~string", ".ID", ".Level"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("symbols %v, want %v", got, want)
+	}
+}
+
+func TestParseGoValueSignatures(t *testing.T) {
+	src := "package m\n\n" +
+		"const Open = \"( a\"\n\n" +
+		"var Usage = `usage:\n  m [ flags ]\n`\n\n" +
+		"var Pairs = map[string]string{\n\t\"a\": \"[ b\",\n}\n\n" +
+		"var Long = \"" + strings.Repeat("x", 100) + "\"\n"
+	symbols := parseGoSource(t, *domain.DefaultConfig(), src)
+
+	tests := []struct {
+		name, signature string
+	}{
+		{"Open", `const Open = "( a"`},
+		{"Usage", "var Usage = `usage:\n  m [ flags ]\n`"},
+		{"Pairs", `var Pairs = map[string]string{"a": "[ b"}`},
+		{"Long", `var Long = "` + strings.Repeat("x", maxValueLen-1) + "..."},
+	}
+	for _, tt := range tests {
+		if got := findSymbol(t, symbols, "", tt.name).Signature; got != tt.signature {
+			t.Errorf("%s signature = %q, want %q", tt.name, got, tt.signature)
+		}
+	}
+}
+
+func TestParseGoFunctions(t *testing.T) {
+	src := `package m
+
+import "fmt"
+
+type List[T any] struct{}
+
+type Map[K comparable, V any] struct{}
+
+type Point struct{}
+
+// Apply maps f over in.
+func Apply[T, U any](in []T, f func(T) U) []U { return nil }
+
+func Sum[N ~int | ~float64](values ...N) (total N) { return }
+
+func (l *List[T]) Push(v T) error { return nil }
+
+func (m Map[K, V]) Get(key K) (V, bool) { var v V; return v, false }
+
+func (p Point) String() string { return fmt.Sprint(p) }
+
+func (p *Point) Move(
+	dx,
+	dy int,
+) {
+}
+
+func (*Point) reset() {}
+`
+	symbols := parseGoSource(t, *domain.DefaultConfig(), src)
+
+	tests := []struct {
+		parent, name, kind, signature string
+	}{
+		{"", "Apply", "function", "func Apply[T, U any](in []T, f func(T) U) []U"},
+		{"", "Sum", "function", "func Sum[N ~int | ~float64](values ...N) (total N)"},
+		{"List", "Push", "method", "func (l *List[T]) Push(v T) error"},
+		{"Map", "Get", "method", "func (m Map[K, V]) Get(key K) (V, bool)"},
+		{"Point", "String", "method", "func (p Point) String() string"},
+		{"Point", "Move", "method", "func (p *Point) Move(dx, dy int)"},
+		{"Point", "reset", "method", "func (*Point) reset()"},
+	}
+	for _, tt := range tests {
+		sym := findSymbol(t, symbols, tt.parent, tt.name)
+		if sym.Kind != tt.kind {
+			t.Errorf("%s kind = %q, want %q", tt.name, sym.Kind, tt.kind)
+		}
+		if sym.Signature != tt.signature {
+			t.Errorf("%s signature = %q, want %q", tt.name, sym.Signature, tt.signature)
+		}
+	}
+}
+
+func TestReceiverTypeName(t *testing.T) {
+	tests := []struct {
+		expr, want string
+	}{
+		{"T", "T"},
+		{"*T", "T"},
+		{"(*T)", "T"},
+		{"List[T]", "List"},
+		{"*List[T]", "List"},
+		{"Map[K, V]", "Map"},
+		{"*Map[K, V]", "Map"},
+		{"pkg.T", "T"},
+		{"*pkg.List[int]", "List"},
+		{"func()", ""},
+	}
+	for _, tt := range tests {
+		expr, err := parser.ParseExpr(tt.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.expr, err)
+		}
+		if got := receiverTypeName(expr); got != tt.want {
+			t.Errorf("receiverTypeName(%s) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,533 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// annotate adds what type checking tells about the symbols of the Go files under root,
+// as configured: the call graph, and the facts of annotateTypes.
+func (p *GoASTParser) annotate(ctx context.Context, root string, symbols []domain.Symbol) []domain.Symbol {
+	config := p.config.Parsing.Go
+	if !config.TypeCheck && !config.CallGraph {
+		return symbols
+	}
+	std := p.importStd
+	if !config.TypeCheck {
+		std = skipStd // The call graph stays within the module
+	}
+	imp := newLocalImporter(root, std)
+	if config.CallGraph {
+		p.annotateCalls(ctx, root, symbols, imp)
+	}
+	if config.TypeCheck {
+		symbols = p.annotateTypes(ctx, root, symbols, imp)
+	}
+	return symbols
+}
+
+var errStdSkipped = errors.New("standard library not loaded")
+
+func skipStd(string) (*types.Package, error) {
+	return nil, errStdSkipped
+}
+
+// annotateTypes type-checks the Go packages (directories) the symbols come from and adds
+// what only type information tells: a "package" symbol per package with its imports,
+// the references of each symbol to other packages, and the interfaces each type
+// implements. Everything is loaded locally, without the network: packages of the
+// enclosing Go module (and of the go.work modules) from source, the standard library
+// from GOROOT; any other import is left opaque.
+func (p *GoASTParser) annotateTypes(ctx context.Context, root string, symbols []domain.Symbol, imp *localImporter) []domain.Symbol {
+	facts := make(map[symbolKey]symbolFacts)
+	var packages []domain.Symbol
+	for _, dir := range symbolDirs(symbols) {
+		if ctx.Err() != nil {
+			return symbols
+		}
+		cp, err := imp.checkDir(filepath.Join(root, dir))
+		if err != nil {
+			slog.WarnContext(ctx, "skipping Go package that failed to load", "dir", filepath.Join(root, dir), "error", err)
+			continue
+		}
+		packages = append(packages, cp.packageSymbol(root))
+		cp.collectFacts(root, facts)
+	}
+
+	for i := range symbols {
+		key := symbolKey{file: symbols[i].FilePath, parent: symbols[i].Parent, name: symbols[i].Name, line: symbols[i].Line}
+		if f, ok := facts[key]; ok {
+			symbols[i].References = f.references
+			symbols[i].Implements = f.implements
+		}
+	}
+	return append(packages, symbols...)
+}
+
+// symbolDirs returns the directories (relative to the module) the symbols come from, sorted.
+func symbolDirs(symbols []domain.Symbol) []string {
+	set := make(map[string]bool)
+	for _, sym := range symbols {
+		set[filepath.Dir(sym.FilePath)] = true
+	}
+	return sortedKeys(set)
+}
+
+// importStd imports a standard library package from source. The importer caches what
+// it loads, so it is shared by every parse of this parser.
+func (p *GoASTParser) importStd(path string) (*types.Package, error) {
+	p.stdMu.Lock()
+	defer p.stdMu.Unlock()
+	if p.std == nil {
+		p.std = importer.ForCompiler(token.NewFileSet(), "source", nil)
+	}
+	return p.std.Import(path)
+}
+
+// symbolKey identifies a symbol across the syntax-only and the type-checked parse.
+type symbolKey struct {
+	file, parent, name string
+	line               int
+}
+
+type symbolFacts struct {
+	references []string
+	implements []string
+}
+
+type checkedPackage struct {
+	fset  *token.FileSet
+	pkg   *types.Package
+	files []*ast.File
+	info  *types.Info
+}
+
+// localImporter resolves imports without the network: packages of the known Go modules
+// are type-checked from source, the standard library is delegated, anything else becomes
+// an empty package so the importing package still type-checks as far as it can.
+type localImporter struct {
+	fset    *token.FileSet
+	modules map[string]string // Module path -> directory
+	std     func(path string) (*types.Package, error)
+	checked map[string]*checkedPackage
+	opaque  map[string]*types.Package
+}
+
+func newLocalImporter(root string, std func(path string) (*types.Package, error)) *localImporter {
+	return &localImporter{
+		fset:    token.NewFileSet(),
+		modules: findGoModules(root),
+		std:     std,
+		checked: make(map[string]*checkedPackage),
+		opaque:  make(map[string]*types.Package),
+	}
+}
+
+func (imp *localImporter) Import(path string) (*types.Package, error) {
+	if cp, ok := imp.checked[path]; ok {
+		return cp.pkg, nil
+	}
+	if pkg, ok := imp.opaque[path]; ok {
+		return pkg, nil
+	}
+	if dir, ok := imp.dirOf(path); ok {
+		if cp, err := imp.check(dir, path); err == nil {
+			return cp.pkg, nil
+		}
+	} else if isStdImport(path) {
+		if pkg, err := imp.std(path); err == nil {
+			return pkg, nil
+		}
+	}
+	pkg := types.NewPackage(path, guessPackageName(path))
+	pkg.MarkComplete()
+	imp.opaque[path] = pkg
+	return pkg, nil
+}
+
+// checkDir type-checks the package in dir, under its import path when a known module holds it.
+func (imp *localImporter) checkDir(dir string) (*checkedPackage, error) {
+	path := imp.importPathOf(dir)
+	if cp, ok := imp.checked[path]; ok {
+		return cp, nil
+	}
+	return imp.check(dir, path)
+}
+
+func (imp *localImporter) check(dir, path string) (*checkedPackage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if ok, err := build.Default.MatchFile(dir, name); err != nil || !ok {
+			continue // Excluded by build constraints on this platform
+		}
+		f, err := parser.ParseFile(imp.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		if len(files) > 0 && f.Name.Name != files[0].Name.Name {
+			continue
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, os.ErrNotExist
+	}
+
+	info := &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{
+		Importer:    imp,
+		FakeImportC: true,
+		Error:       func(error) {}, // Check as much as possible: opaque imports leave holes
+	}
+	pkg, _ := conf.Check(path, imp.fset, files, info)
+	cp := &checkedPackage{fset: imp.fset, pkg: pkg, files: files, info: info}
+	imp.checked[path] = cp
+	return cp, nil
+}
+
+// dirOf returns the directory of an import path inside one of the known modules.
+func (imp *localImporter) dirOf(path string) (string, bool) {
+	best := ""
+	for modPath := range imp.modules {
+		if (path == modPath || strings.HasPrefix(path, modPath+"/")) && len(modPath) > len(best) {
+			best = modPath
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	return filepath.Join(imp.modules[best], filepath.FromSlash(strings.TrimPrefix(path, best))), true
+}
+
+// importPathOf is the reverse of dirOf; outside the known modules the directory itself is the path.
+func (imp *localImporter) importPathOf(dir string) string {
+	best, bestDir := "", ""
+	for modPath, modDir := range imp.modules {
+		rel, err := filepath.Rel(modDir, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if len(modDir) > len(bestDir) {
+			best, bestDir = modPath, modDir
+			if rel != "." {
+				best += "/" + filepath.ToSlash(rel)
+			}
+		}
+	}
+	if best == "" {
+		return filepath.ToSlash(dir)
+	}
+	return best
+}
+
+// findGoModules returns the Go module enclosing root and, if a go.work encloses it, the
+// modules it uses.
+func findGoModules(root string) map[string]string {
+	modules := make(map[string]string)
+	foundMod, foundWork := false, false
+	for dir := root; ; dir = filepath.Dir(dir) {
+		if !foundMod {
+			if modPath := readModulePath(filepath.Join(dir, "go.mod")); modPath != "" {
+				modules[modPath] = dir
+				foundMod = true
+			}
+		}
+		if !foundWork {
+			if data, err := os.ReadFile(filepath.Join(dir, "go.work")); err == nil {
+				for _, use := range parseWorkUses(string(data)) {
+					useDir := filepath.Join(dir, filepath.FromSlash(use))
+					if modPath := readModulePath(filepath.Join(useDir, "go.mod")); modPath != "" {
+						modules[modPath] = useDir
+					}
+				}
+				foundWork = true
+			}
+		}
+		if parent := filepath.Dir(dir); parent == dir || (foundMod && foundWork) {
+			break
+		}
+	}
+	return modules
+}
+
+func readModulePath(gomod string) string {
+	data, err := os.ReadFile(gomod)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+// parseWorkUses returns the directories of the use directives of a go.work file.
+func parseWorkUses(data string) []string {
+	var uses []string
+	inBlock := false
+	for _, line := range strings.Split(data, "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case inBlock && fields[0] == ")":
+			inBlock = false
+		case inBlock:
+			uses = append(uses, strings.Trim(fields[0], `"`))
+		case fields[0] == "use" && len(fields) >= 2 && fields[1] == "(":
+			inBlock = true
+		case fields[0] == "use" && len(fields) >= 2:
+			uses = append(uses, strings.Trim(fields[1], `"`))
+		}
+	}
+	return uses
+}
+
+// isStdImport reports whether path looks like a standard library package (no dot in its first element).
+func isStdImport(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+// guessPackageName derives the name of a package that was not loaded from its import path:
+// gopkg.in/yaml.v3 is yaml, github.com/x/go-foo/v2 is foo.
+func guessPackageName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && strings.HasPrefix(name, "v") {
+		if _, err := strconv.Atoi(name[1:]); err == nil {
+			name = parts[len(parts)-2]
+		}
+	}
+	if i := strings.Index(name, ".v"); i > 0 {
+		name = name[:i]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	return strings.NewReplacer("-", "", ".", "").Replace(name)
+}
+
+// packageSymbol describes the package itself: its doc comment and what it imports.
+func (cp *checkedPackage) packageSymbol(root string) domain.Symbol {
+	first := cp.files[0]
+	pos := cp.fset.Position(first.Package)
+	rel, _ := filepath.Rel(root, pos.Filename)
+
+	sym := domain.Symbol{
+		Name:      cp.pkg.Name(),
+		Kind:      "package",
+		Exported:  true,
+		Line:      pos.Line,
+		LineEnd:   pos.Line,
+		FilePath:  rel,
+		Signature: "package " + cp.pkg.Name(),
+	}
+	seen := make(map[string]bool)
+	for _, f := range cp.files {
+		if sym.Docstring == "" && f.Doc != nil {
+			sym.Docstring = strings.TrimSpace(f.Doc.Text())
+		}
+		for _, spec := range f.Imports {
+			if path, err := strconv.Unquote(spec.Path.Value); err == nil && !seen[path] {
+				seen[path] = true
+				sym.Imports = append(sym.Imports, path)
+			}
+		}
+	}
+	sort.Strings(sym.Imports)
+	return sym
+}
+
+// collectFacts records the references and implemented interfaces of the package-level
+// declarations, keyed like the symbols parseFile produces for them.
+func (cp *checkedPackage) collectFacts(root string, facts map[symbolKey]symbolFacts) {
+	ifaces := cp.candidateInterfaces()
+	for _, f := range cp.files {
+		rel, _ := filepath.Rel(root, cp.fset.Position(f.Package).Filename)
+		key := func(parent, name string, pos token.Pos) symbolKey {
+			return symbolKey{file: rel, parent: parent, name: name, line: cp.fset.Position(pos).Line}
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				parent := ""
+				if d.Recv != nil && len(d.Recv.List) > 0 {
+					parent = receiverTypeName(d.Recv.List[0].Type)
+				}
+				facts[key(parent, d.Name.Name, d.Pos())] = symbolFacts{references: cp.references(d)}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						facts[key("", s.Name.Name, s.Pos())] = symbolFacts{
+							references: cp.references(s.Type),
+							implements: cp.implements(s, ifaces),
+						}
+					case *ast.ValueSpec:
+						for i, name := range s.Names {
+							var nodes []ast.Node
+							if s.Type != nil {
+								nodes = append(nodes, s.Type)
+							}
+							if i < len(s.Values) {
+								nodes = append(nodes, s.Values[i])
+							}
+							facts[key("", name.Name, name.Pos())] = symbolFacts{references: cp.references(nodes...)}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// references returns the objects of other packages used within nodes, sorted.
+func (cp *checkedPackage) references(nodes ...ast.Node) []string {
+	seen := make(map[string]bool)
+	for _, node := range nodes {
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				// An opaque package resolves nothing: name the selector after the import
+				if x, ok := n.X.(*ast.Ident); ok {
+					if pkgName, ok := cp.info.Uses[x].(*types.PkgName); ok && cp.info.Uses[n.Sel] == nil {
+						seen[pkgName.Imported().Name()+"."+n.Sel.Name] = true
+					}
+				}
+			case *ast.Ident:
+				if ref := cp.qualifiedName(cp.info.Uses[n]); ref != "" {
+					seen[ref] = true
+				}
+			}
+			return true
+		})
+	}
+	return sortedKeys(seen)
+}
+
+// qualifiedName names a package-level object, or a method, of another package: "pkg.Name"
+// or "pkg.Type.Method". Anything else (local, universe, fields) yields "".
+func (cp *checkedPackage) qualifiedName(obj types.Object) string {
+	if obj == nil || obj.Pkg() == nil || obj.Pkg() == cp.pkg {
+		return ""
+	}
+	if fn, ok := obj.(*types.Func); ok {
+		if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+			if typeName := namedTypeName(recv.Type()); typeName != "" {
+				return obj.Pkg().Name() + "." + typeName + "." + obj.Name()
+			}
+			return ""
+		}
+	}
+	if _, ok := obj.(*types.PkgName); ok || obj.Parent() != obj.Pkg().Scope() {
+		return ""
+	}
+	return obj.Pkg().Name() + "." + obj.Name()
+}
+
+// candidateInterfaces are the non-empty, non-generic interfaces a type of the package
+// could implement: its own and those of the packages it imports directly.
+func (cp *checkedPackage) candidateInterfaces() []*types.TypeName {
+	var ifaces []*types.TypeName
+	add := func(pkg *types.Package) {
+		scope := pkg.Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || tn.IsAlias() {
+				continue
+			}
+			named, ok := tn.Type().(*types.Named)
+			if !ok || named.TypeParams().Len() > 0 {
+				continue
+			}
+			if iface, ok := named.Underlying().(*types.Interface); ok && iface.NumMethods() > 0 {
+				ifaces = append(ifaces, tn)
+			}
+		}
+	}
+	add(cp.pkg)
+	for _, imported := range cp.pkg.Imports() {
+		add(imported)
+	}
+	return ifaces
+}
+
+// implements returns the candidate interfaces the type declared by spec, or a pointer to
+// it, implements: "Interface" in its own package, "pkg.Interface" in another.
+func (cp *checkedPackage) implements(spec *ast.TypeSpec, ifaces []*types.TypeName) []string {
+	tn, ok := cp.info.Defs[spec.Name].(*types.TypeName)
+	if !ok || tn.IsAlias() {
+		return nil
+	}
+	named, ok := tn.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		return nil
+	}
+	if _, isIface := named.Underlying().(*types.Interface); isIface {
+		return nil
+	}
+	var out []string
+	for _, candidate := range ifaces {
+		iface := candidate.Type().Underlying().(*types.Interface)
+		if types.Implements(named, iface) || types.Implements(types.NewPointer(named), iface) {
+			name := candidate.Name()
+			if candidate.Pkg() != cp.pkg {
+				name = candidate.Pkg().Name() + "." + name
+			}
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// namedTypeName returns the name of a (pointer to a) named type, or "".
+func namedTypeName(t types.Type) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return ""
+}
+
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
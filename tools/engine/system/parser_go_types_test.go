@@ -0,0 +1,149 @@
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+func TestTypeCheckedFacts(t *testing.T) {
+	work := t.TempDir()
+	files := map[string]string{
+		"go.work":              "go 1.22\n\nuse (\n\t./app\n\t./lib\n)\n",
+		"lib/go.mod":           "module example.com/lib\n\ngo 1.22\n",
+		"lib/lib.go":           "package lib\n\nfunc Name() string { return \"lib\" }\n",
+		"app/go.mod":           "module example.com/app\n\ngo 1.22\n\nrequire github.com/acme/ext v1.0.0\n",
+		"app/domain/domain.go": "// Package domain holds the ports.\npackage domain\n\ntype FileSystem interface {\n\tReadFile(name string) ([]byte, error)\n}\n",
+		"app/system/fs.go": `package system
+
+import (
+	"os"
+
+	"example.com/app/domain"
+	"example.com/lib"
+	"github.com/acme/ext"
+)
+
+type RealFileSystem struct{}
+
+func (RealFileSystem) ReadFile(name string) ([]byte, error) { return os.ReadFile(name) }
+
+var Default domain.FileSystem = RealFileSystem{}
+
+func Decode(data []byte) ext.Doc { return ext.Parse(data) }
+
+func Label() string { return lib.Name() }
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(work, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Any go command (go list, module download) would leave a mark
+	bin, mark := t.TempDir(), filepath.Join(t.TempDir(), "go-was-run")
+	if err := os.WriteFile(filepath.Join(bin, "go"), []byte("#!/bin/sh\n: > "+mark+"\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+	t.Setenv("GOPROXY", "off")
+
+	config := *domain.DefaultConfig()
+	config.Parsing.Go.TypeCheck = true
+	symbols, err := NewGoASTParser(config).ParseDir(context.Background(), filepath.Join(work, "app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(mark); err == nil {
+		t.Error("type checking ran the go command")
+	}
+
+	var packages []string
+	for _, sym := range symbols {
+		if sym.Kind == "package" {
+			packages = append(packages, sym.FilePath+" "+sym.Name)
+		}
+	}
+	if want := []string{filepath.Join("domain", "domain.go") + " domain", filepath.Join("system", "fs.go") + " system"}; !reflect.DeepEqual(packages, want) {
+		t.Errorf("package symbols %v, want %v", packages, want)
+	}
+	domainPkg := findSymbol(t, symbols, "", "domain")
+	if domainPkg.Docstring != "Package domain holds the ports." {
+		t.Errorf("domain package doc = %q", domainPkg.Docstring)
+	}
+	systemPkg := findSymbol(t, symbols, "", "system")
+	if want := []string{"example.com/app/domain", "example.com/lib", "github.com/acme/ext", "os"}; !reflect.DeepEqual(systemPkg.Imports, want) {
+		t.Errorf("system imports %v, want %v", systemPkg.Imports, want)
+	}
+
+	tests := []struct {
+		parent, name string
+		references   []string
+		implements   []string
+	}{
+		{"", "RealFileSystem", nil, []string{"domain.FileSystem"}},
+		{"RealFileSystem", "ReadFile", []string{"os.ReadFile"}, nil},
+		{"", "Default", []string{"domain.FileSystem"}, nil},
+		{"", "Decode", []string{"ext.Doc", "ext.Parse"}, nil}, // Opaque: named after the import
+		{"", "Label", []string{"lib.Name"}, nil},              // From the go.work sibling's source
+	}
+	for _, tt := range tests {
+		sym := findSymbol(t, symbols, tt.parent, tt.name)
+		if !reflect.DeepEqual(sym.References, tt.references) {
+			t.Errorf("%s references %v, want %v", tt.name, sym.References, tt.references)
+		}
+		if !reflect.DeepEqual(sym.Implements, tt.implements) {
+			t.Errorf("%s implements %v, want %v", tt.name, sym.Implements, tt.implements)
+		}
+	}
+}
+
+func TestGuessPackageName(t *testing.T) {
+	tests := map[string]string{
+		"gopkg.in/yaml.v3":         "yaml",
+		"github.com/x/go-foo/v2":   "foo",
+		"github.com/acme/ext":      "ext",
+		"example.com/my-lib":       "mylib",
+		"github.com/x/vendor.name": "vendorname",
+	}
+	for path, want := range tests {
+		if got := guessPackageName(path); got != want {
+			t.Errorf("guessPackageName(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestParseWorkUses(t *testing.T) {
+	data := "go 1.22\n\nuse ./single // comment\n\nuse (\n\t./a\n\t\"./b\"\n)\n"
+	if got, want := parseWorkUses(data), []string{"./single", "./a", "./b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseWorkUses = %v, want %v", got, want)
+	}
+}
+
+func TestImportPathOf(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "work")
+	imp := &localImporter{modules: map[string]string{
+		"example.com/app":     filepath.Join(root, "app"),
+		"example.com/app/lib": filepath.Join(root, "app", "lib"),
+	}}
+	tests := map[string]string{
+		filepath.Join(root, "app"):                 "example.com/app",
+		filepath.Join(root, "app", "..gen"):        "example.com/app/..gen",
+		filepath.Join(root, "app", "lib", "codec"): "example.com/app/lib/codec",
+		filepath.Join(root, "other"):               filepath.ToSlash(filepath.Join(root, "other")),
+	}
+	for dir, want := range tests {
+		if got := imp.importPathOf(dir); got != want {
+			t.Errorf("importPathOf(%s) = %q, want %q", dir, got, want)
+		}
+	}
+}
@@ -0,0 +1,993 @@
+package system
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// JSParser extracts the functions, classes (with their methods and fields), interfaces,
+// type aliases, enums and exported variables of JavaScript and TypeScript sources, without
+// ctags. It tokenizes each file and reads its top-level declarations, skipping the bodies
+// of functions by matching brackets; variables holding a function, such as an exported
+// arrow function, are reported as functions.
+type JSParser struct {
+	sourceParser
+}
+
+// jsParserVersion changes whenever parseJS would produce different symbols for the same file.
+const jsParserVersion = "js/3"
+
+func NewJSParser(config domain.Config) *JSParser {
+	return &JSParser{sourceParser{
+		language:       "JavaScript",
+		version:        jsParserVersion,
+		extensions:     config.Parsing.JavaScript.Extensions,
+		ignorePatterns: config.IgnorePatterns,
+		parse:          parseJS,
+	}}
+}
+
+type jsTokenKind int
+
+const (
+	jsIdent   jsTokenKind = iota // Identifiers and keywords, including #private names
+	jsPunct                      // Operators and brackets
+	jsLiteral                    // Strings, template literals, regular expressions and numbers
+)
+
+type jsToken struct {
+	kind          jsTokenKind
+	text          string
+	start, end    int  // Offsets in the source
+	line, endLine int  // 1-based
+	nl            bool // First token of its line
+	doc           string
+}
+
+// parseJS extracts the symbols of one JavaScript or TypeScript file.
+func parseJS(src []byte) []domain.Symbol {
+	p := &jsParser{toks: jsTokenize(src), bodiless: make(map[int]bool), exportedNames: make(map[string]bool)}
+	for i := 0; i < len(p.toks); {
+		next := p.statement(i, len(p.toks))
+		if next <= i {
+			next = i + 1
+		}
+		i = next
+	}
+	return p.finish()
+}
+
+type jsParser struct {
+	toks     []jsToken
+	syms     []domain.Symbol
+	bodiless map[int]bool // Functions and methods declared without a body: overloads, abstract or ambient
+
+	exportedNames map[string]bool // Names exported apart from their declaration: export { a }, exports.a = a
+}
+
+func (p *jsParser) text(k int) string {
+	if k < 0 || k >= len(p.toks) {
+		return ""
+	}
+	return p.toks[k].text
+}
+
+func (p *jsParser) isIdent(k int) bool {
+	return k >= 0 && k < len(p.toks) && p.toks[k].kind == jsIdent
+}
+
+func (p *jsParser) isPunct(k int, text string) bool {
+	return k >= 0 && k < len(p.toks) && p.toks[k].kind == jsPunct && p.toks[k].text == text
+}
+
+// statement reads the statement starting at token i, recording what it declares, and
+// returns the index of the token after it.
+func (p *jsParser) statement(i, limit int) int {
+	start := i
+	decorators, i := p.decorators(i, limit)
+	decl := i
+	exported, isDefault := false, false
+modifiers:
+	for ; p.isIdent(i) && i+1 < limit; i++ {
+		switch p.text(i) {
+		case "export":
+			exported = true
+		case "default":
+			if !exported {
+				break modifiers
+			}
+			isDefault = true
+		case "declare", "abstract", "async":
+			if !p.isIdent(i+1) || p.toks[i+1].nl {
+				break modifiers // Used as a name
+			}
+		default:
+			break modifiers
+		}
+	}
+	if i >= limit {
+		return limit
+	}
+
+	sym := domain.Symbol{
+		Exported:   exported,
+		Line:       p.toks[decl].line,
+		Docstring:  jsDocText(p.toks[start].doc),
+		Decorators: decorators,
+	}
+	switch word := p.text(i); {
+	case exported && p.isPunct(i, "{"):
+		return p.exportList(i, limit)
+	case !p.isIdent(i):
+	case word == "function":
+		return p.function(sym, decl, i, limit, isDefault)
+	case word == "class":
+		return p.class(sym, decl, i, limit, isDefault)
+	case word == "interface" && p.isIdent(i+1):
+		return p.container(sym, "interface", decl, i, limit)
+	case word == "enum" && p.isIdent(i+1):
+		return p.container(sym, "enum", decl, i, limit)
+	case word == "const" && p.text(i+1) == "enum" && p.isIdent(i+2):
+		return p.container(sym, "enum", decl, i+1, limit)
+	case word == "type" && p.isIdent(i+1) && (p.isPunct(i+2, "=") || p.isPunct(i+2, "<")):
+		return p.typeAlias(sym, decl, i, limit)
+	case word == "const" || word == "let" || word == "var":
+		return p.variables(sym, decl, i, limit)
+	case !exported && (word == "exports" || word == "module"):
+		return p.commonJS(sym, i, limit)
+	}
+	return p.statementEnd(i, limit, false) + 1
+}
+
+// function reads a function declaration, the "function" keyword being at i.
+func (p *jsParser) function(sym domain.Symbol, decl, i, limit int, isDefault bool) int {
+	k := i + 1
+	if p.isPunct(k, "*") {
+		k++
+	}
+	sym.Kind, sym.Name = "function", "default"
+	if p.isIdent(k) {
+		sym.Name = p.text(k)
+		k++
+	} else if !isDefault {
+		return p.statementEnd(i, limit, false) + 1 // A function expression
+	}
+	return p.callable(sym, decl, k, limit, false)
+}
+
+// callable records a function or method whose parameters start at k, and returns the
+// index of the token after its body, or after the declaration when it has none.
+func (p *jsParser) callable(sym domain.Symbol, decl, k, limit int, member bool) int {
+	body := p.findBody(k, limit)
+	if body < 0 {
+		end := p.statementEnd(k, limit, member)
+		stop := p.trimTerminator(end)
+		if stop < decl {
+			return end + 1
+		}
+		sym.Signature = p.signature(decl, stop)
+		sym.LineEnd = p.toks[end].endLine
+		p.bodiless[len(p.syms)] = true
+		p.syms = append(p.syms, sym)
+		return end + 1
+	}
+	closing := p.skipBalanced(body) - 1
+	sym.Signature = p.signature(decl, body-1)
+	sym.LineEnd = p.toks[min(closing, len(p.toks)-1)].endLine
+	p.syms = append(p.syms, sym)
+	return closing + 1
+}
+
+// class reads a class declaration, the "class" keyword being at i, and its members.
+func (p *jsParser) class(sym domain.Symbol, decl, i, limit int, isDefault bool) int {
+	k := i + 1
+	sym.Kind, sym.Name = "class", "default"
+	if p.isIdent(k) && p.text(k) != "extends" && p.text(k) != "implements" {
+		sym.Name = p.text(k)
+		k++
+	} else if !isDefault {
+		return p.statementEnd(i, limit, false) + 1 // A class expression
+	}
+	body := p.findBody(k, limit)
+	if body < 0 {
+		return p.statementEnd(i, limit, false) + 1
+	}
+	closing := p.skipBalanced(body) - 1
+	sym.Signature = p.signature(decl, body-1)
+	sym.LineEnd = p.toks[min(closing, len(p.toks)-1)].endLine
+	p.syms = append(p.syms, sym)
+	p.members(sym.Name, body+1, closing, false)
+	return closing + 1
+}
+
+// container reads an interface (with its members) or an enum, its keyword being at i.
+func (p *jsParser) container(sym domain.Symbol, kind string, decl, i, limit int) int {
+	sym.Kind, sym.Name = kind, p.text(i+1)
+	body := p.findBody(i+2, limit)
+	if body < 0 {
+		return p.statementEnd(i, limit, false) + 1
+	}
+	closing := p.skipBalanced(body) - 1
+	sym.Signature = p.signature(decl, body-1)
+	sym.LineEnd = p.toks[min(closing, len(p.toks)-1)].endLine
+	p.syms = append(p.syms, sym)
+	if kind == "interface" {
+		p.members(sym.Name, body+1, closing, true)
+	}
+	return closing + 1
+}
+
+// typeAlias reads a TypeScript type alias, the "type" keyword being at i.
+func (p *jsParser) typeAlias(sym domain.Symbol, decl, i, limit int) int {
+	end := p.statementEnd(i, limit, false)
+	eq := i + 2
+	if p.isPunct(eq, "<") {
+		eq = p.angleEnd(eq, end) + 1
+	}
+	if !p.isPunct(eq, "=") {
+		return end + 1
+	}
+	sym.Kind, sym.Name = "type", p.text(i+1)
+	sym.Signature = p.signature(decl, eq-1) + " = " + capValue(p.signature(eq+1, p.trimTerminator(end)))
+	sym.LineEnd = p.toks[end].endLine
+	p.syms = append(p.syms, sym)
+	return end + 1
+}
+
+// variables reads a const, let or var statement, its keyword being at i. Variables holding
+// a function are recorded as functions; the others only when they are exported.
+func (p *jsParser) variables(sym domain.Symbol, decl, i, limit int) int {
+	end := p.statementEnd(i, limit, false)
+	stop := p.trimTerminator(end)
+	for k := i + 1; k <= stop; {
+		last := p.declaratorEnd(k, stop)
+		if p.isIdent(k) {
+			v := sym
+			if k != i+1 {
+				v.Line = p.toks[k].line
+			}
+			p.variable(v, decl, i, k, last)
+		}
+		k = last + 2 // Past the comma
+	}
+	return end + 1
+}
+
+// variable records the declarator from k to last of the variable statement whose keyword is at keyword.
+func (p *jsParser) variable(sym domain.Symbol, decl, keyword, k, last int) {
+	sym.Name = p.text(k)
+	sym.LineEnd = p.toks[last].endLine
+	prefix := p.signature(decl, keyword)
+	eq, fn := p.find(k+1, last, "="), -1
+	if eq >= 0 {
+		fn = p.functionValue(eq+1, last)
+	}
+	switch {
+	case fn >= 0:
+		sym.Kind = "function"
+		sym.Signature = prefix + " " + p.signature(k, fn)
+	case !sym.Exported:
+		return
+	case eq >= 0:
+		sym.Kind = jsVariableKind(p.text(keyword))
+		sym.Signature = prefix + " " + p.signature(k, eq-1) + " = " + capValue(p.signature(eq+1, last))
+	default:
+		sym.Kind = jsVariableKind(p.text(keyword))
+		sym.Signature = prefix + " " + p.signature(k, last)
+	}
+	p.syms = append(p.syms, sym)
+}
+
+func jsVariableKind(keyword string) string {
+	if keyword == "const" {
+		return "const"
+	}
+	return "var"
+}
+
+// commonJS reads an "exports.name = ..." or "module.exports.name = ..." assignment, the
+// statement starting at i: a function or literal value is recorded, while a local name
+// ("exports.a = a", "module.exports = { a, b }") only marks its declaration exported.
+func (p *jsParser) commonJS(sym domain.Symbol, i, limit int) int {
+	end := p.statementEnd(i, limit, false)
+	last := p.trimTerminator(end)
+	k := i
+	if p.text(k) == "module" && p.isPunct(k+1, ".") && p.text(k+2) == "exports" {
+		k += 2
+		if p.isPunct(k+1, "=") {
+			p.exportNames(k+2, last)
+			return end + 1
+		}
+	}
+	if p.text(k) != "exports" || !p.isPunct(k+1, ".") || !p.isIdent(k+2) || !p.isPunct(k+3, "=") {
+		return end + 1
+	}
+	value := k + 4
+	sym.Name, sym.Exported, sym.LineEnd = p.text(k+2), true, p.toks[end].endLine
+	switch {
+	case p.functionValue(value, last) >= 0:
+		sym.Kind, sym.Signature = "function", p.signature(i, p.functionValue(value, last))
+	case value == last && p.toks[value].kind == jsLiteral:
+		sym.Kind, sym.Signature = "var", p.signature(i, last)
+	default:
+		p.exportNames(value, last)
+		return end + 1
+	}
+	p.syms = append(p.syms, sym)
+	return end + 1
+}
+
+// exportList reads an "export { a, b as c }" statement, the "{" being at i. A list
+// re-exported from another module declares nothing here.
+func (p *jsParser) exportList(i, limit int) int {
+	end := p.statementEnd(i, limit, false)
+	if closing := p.skipBalanced(i); p.text(closing) != "from" {
+		p.exportNames(i, closing-1)
+	}
+	return end + 1
+}
+
+// exportNames marks exported the local names the value from i to last consists of: a
+// name, or the names of an object literal or export list.
+func (p *jsParser) exportNames(i, last int) {
+	if i == last && p.isIdent(i) {
+		p.exportedNames[p.text(i)] = true
+		return
+	}
+	if !p.isPunct(i, "{") {
+		return
+	}
+	for k := i + 1; k < last; k++ {
+		// "a", "a as b" and "key: a" name the local a
+		if p.isIdent(k) && (p.isPunct(k-1, "{") || p.isPunct(k-1, ",") || p.isPunct(k-1, ":")) {
+			if next := p.text(k + 1); next == "," || next == "}" || next == "as" {
+				p.exportedNames[p.text(k)] = true
+			}
+		}
+		if p.isPunct(k, "(") || p.isPunct(k, "[") || p.isPunct(k, "{") {
+			k = p.skipBalanced(k) - 1
+		}
+	}
+}
+
+// jsMemberModifiers precede the name of a class or interface member.
+var jsMemberModifiers = map[string]bool{
+	"public": true, "private": true, "protected": true, "static": true, "readonly": true, "abstract": true,
+	"async": true, "override": true, "declare": true, "accessor": true, "get": true, "set": true,
+}
+
+// members reads the members of a class or interface, between tokens i and limit.
+func (p *jsParser) members(parent string, i, limit int, iface bool) {
+	for i < limit {
+		if p.isPunct(i, ";") || p.isPunct(i, ",") {
+			i++
+			continue
+		}
+		next := p.member(parent, i, limit, iface)
+		if next <= i {
+			next = i + 1
+		}
+		i = next
+	}
+}
+
+// member reads one class or interface member: a method, or a field; a field holding a
+// function is recorded as a method.
+func (p *jsParser) member(parent string, i, limit int, iface bool) int {
+	start := i
+	decorators, i := p.decorators(i, limit)
+	decl := i
+	private := false
+	for ; p.isIdent(i) && jsMemberModifiers[p.text(i)] && i+1 < limit; i++ {
+		if next := p.toks[i+1]; next.kind == jsPunct && next.text != "[" && next.text != "*" {
+			break // The modifier is the member's name, as in get() or static = 1
+		}
+		if p.text(i) == "private" || p.text(i) == "protected" {
+			private = true
+		}
+	}
+	if p.isPunct(i, "*") {
+		i++
+	}
+	if i >= limit {
+		return limit
+	}
+
+	var name string
+	switch t := p.toks[i]; {
+	case t.kind == jsIdent && t.text == "static" && p.isPunct(i+1, "{"):
+		return p.skipBalanced(i + 1) // A static initialization block
+	case t.kind == jsIdent && t.text == "new" && iface && p.isPunct(i+1, "("):
+		return p.statementEnd(i, limit, true) + 1 // A construct signature
+	case t.kind == jsIdent || t.kind == jsLiteral:
+		name = strings.Trim(t.text, "\"'")
+		i++
+	case t.kind == jsPunct && t.text == "[":
+		closing := p.skipBalanced(i)
+		if p.find(i+1, closing-2, ":") >= 0 {
+			return p.statementEnd(i, limit, true) + 1 // An index signature
+		}
+		name = p.signature(i, closing-1)
+		i = closing
+	default:
+		return p.statementEnd(i, limit, true) + 1 // A call signature
+	}
+	if p.isPunct(i, "?") || p.isPunct(i, "!") {
+		i++
+	}
+
+	sym := domain.Symbol{
+		Name:       name,
+		Parent:     parent,
+		Exported:   !private && !strings.HasPrefix(name, "#"), // And its owner's, once finish knows it
+		Line:       p.toks[decl].line,
+		Docstring:  jsDocText(p.toks[start].doc),
+		Decorators: decorators,
+	}
+	if p.isPunct(i, "(") || p.isPunct(i, "<") {
+		sym.Kind = "method"
+		return p.callable(sym, decl, i, limit, true)
+	}
+
+	end := p.statementEnd(i, limit, true)
+	last := p.trimTerminator(end)
+	if last < decl {
+		return end + 1
+	}
+	sym.Kind, sym.LineEnd = "field", p.toks[end].endLine
+	sym.Signature = p.signature(decl, last)
+	if eq := p.find(i, last, "="); eq >= 0 {
+		if fn := p.functionValue(eq+1, last); fn >= 0 {
+			sym.Kind, sym.Signature = "method", p.signature(decl, fn)
+		} else {
+			sym.Signature = p.signature(decl, eq-1) + " = " + capValue(p.signature(eq+1, last))
+		}
+	}
+	p.syms = append(p.syms, sym)
+	return end + 1
+}
+
+// decorators reads the decorators starting at i, returning them without their "@" and
+// the index of the token after them.
+func (p *jsParser) decorators(i, limit int) ([]string, int) {
+	var out []string
+	for i < limit && p.isPunct(i, "@") {
+		k := i + 1
+		for k < limit && (p.isIdent(k) || p.isPunct(k, ".")) {
+			k++
+		}
+		if p.isPunct(k, "(") {
+			k = p.skipBalanced(k)
+		}
+		out = append(out, p.signature(i+1, k-1))
+		i = k
+	}
+	return out, i
+}
+
+// functionValue reports whether the expression from k to last is a function: it returns
+// the index of the "=>" of an arrow function, or of the token before the body of a
+// function expression, or -1.
+func (p *jsParser) functionValue(k, last int) int {
+	if p.isPunct(k, "(") && p.skipBalanced(k)-1 == last {
+		return p.functionValue(k+1, last-1) // Parenthesized
+	}
+	if p.text(k) == "async" && k < last {
+		k++
+	}
+	switch {
+	case p.text(k) == "function":
+		if body := p.findBody(k+1, last+1); body >= 0 && p.skipBalanced(body)-1 == last {
+			return body - 1
+		}
+		return -1 // Not a function, or one called right away
+	case p.isIdent(k) && p.isPunct(k+1, "=>"):
+		return k + 1
+	}
+	if p.isPunct(k, "<") {
+		k = p.angleEnd(k, last) + 1 // Type parameters
+	}
+	if !p.isPunct(k, "(") {
+		return -1
+	}
+	k = p.skipBalanced(k)
+	if p.isPunct(k, "=>") {
+		return k
+	}
+	if !p.isPunct(k, ":") {
+		return -1
+	}
+	for ; k <= last; k++ { // A return type, up to the arrow
+		switch {
+		case p.isPunct(k, "=>") && k > 0 && !p.isPunct(k-1, ":"):
+			return k
+		case p.isPunct(k, "(") || p.isPunct(k, "[") || p.isPunct(k, "{"):
+			k = p.skipBalanced(k) - 1
+		}
+	}
+	return -1
+}
+
+// findBody returns the index of the "{" opening the body of the declaration whose header
+// continues at k, or -1 when the declaration ends first. Braces of object types, as in
+// a return type, are told apart by the token before them.
+func (p *jsParser) findBody(k, limit int) int {
+	for ; k < limit; k++ {
+		t := p.toks[k]
+		if t.kind == jsPunct {
+			switch t.text {
+			case "{":
+				if k > 0 && jsTypeContext(p.toks[k-1]) {
+					k = p.skipBalanced(k) - 1
+					continue
+				}
+				return k
+			case "(", "[":
+				k = p.skipBalanced(k) - 1
+				continue
+			case "<": // Type parameters, which may have defaults
+				k = p.angleEnd(k, limit-1)
+				continue
+			case ";", "}", "=":
+				return -1
+			}
+		}
+		if k+1 < limit && p.toks[k+1].nl && p.toks[k+1].text != "{" && p.asiEnds(k) {
+			return -1
+		}
+	}
+	return -1
+}
+
+// jsTypeContext reports whether a "{" after t opens an object type rather than a body.
+func jsTypeContext(t jsToken) bool {
+	if t.kind == jsIdent {
+		return t.text == "extends" || t.text == "implements" || t.text == "keyof" || t.text == "typeof"
+	}
+	switch t.text {
+	case ":", "=>", "|", "&", "<", ",", "?", "(", "[":
+		return true
+	}
+	return false
+}
+
+// statementEnd returns the index of the last token of the statement (or member) starting
+// at i: a semicolon, the token before a line break where automatic semicolon insertion
+// applies, or the token before a bracket closing an enclosing block. With commaEnds, as
+// between the members of an interface, a comma also ends it.
+func (p *jsParser) statementEnd(i, limit int, commaEnds bool) int {
+	depth := 0
+	for k := i; k < limit; k++ {
+		t := p.toks[k]
+		if t.kind == jsPunct {
+			switch t.text {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				if depth == 0 {
+					return k - 1
+				}
+				depth--
+			case ";":
+				if depth == 0 {
+					return k
+				}
+			case ",":
+				if depth == 0 && commaEnds {
+					return k
+				}
+			}
+		}
+		if depth == 0 && k+1 < limit && p.toks[k+1].nl && p.asiEnds(k) {
+			return k
+		}
+	}
+	return limit - 1
+}
+
+// asiEnds reports whether a statement ends at token k, followed by a line break.
+func (p *jsParser) asiEnds(k int) bool {
+	return !jsContinuesAfter(p.toks[k]) && !jsContinuesBefore(p.toks[k+1])
+}
+
+// jsContinuesAfter reports whether an expression or type cannot end with t.
+func jsContinuesAfter(t jsToken) bool {
+	switch t.kind {
+	case jsPunct:
+		switch t.text {
+		case ")", "]", "}", ">", ">>", "++", "--", "!": // ">>" closes nested type arguments
+			return false
+		}
+		return true
+	case jsIdent:
+		switch t.text {
+		case "extends", "implements", "instanceof", "in", "new", "as", "satisfies", "keyof", "typeof":
+			return true
+		}
+	}
+	return false
+}
+
+// jsContinuesBefore reports whether a line starting with t continues the previous one.
+// Unlike the language, it takes a line starting with "(" or "[" to start a new statement
+// or member, as in an index signature; code relying on the other reading is rare.
+func jsContinuesBefore(t jsToken) bool {
+	switch t.kind {
+	case jsPunct:
+		switch t.text {
+		case ".", "?.", "=>", "|", "&", "&&", "||", "??", "?", ":", "=", ",", "*", "/", "+", "-", "<", ">":
+			return true
+		}
+	case jsIdent:
+		switch t.text {
+		case "extends", "implements", "instanceof", "in", "as", "satisfies":
+			return true
+		}
+	}
+	return false
+}
+
+// declaratorEnd returns the index of the last token of the declarator starting at k, up to
+// a comma followed by another declarator ("a = 1, b = 2", not "Map<K, V>") or last.
+func (p *jsParser) declaratorEnd(k, last int) int {
+	depth := 0
+	for ; k <= last; k++ {
+		t := p.toks[k]
+		if t.kind != jsPunct {
+			continue
+		}
+		switch t.text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+		case ",":
+			if depth == 0 && p.isIdent(k+1) && (k+2 > last || p.isPunct(k+2, "=") || p.isPunct(k+2, ":") || p.isPunct(k+2, ",")) {
+				return k - 1
+			}
+		}
+	}
+	return last
+}
+
+// find returns the index of the first punctuation text at bracket depth 0 between i and last, or -1.
+func (p *jsParser) find(i, last int, text string) int {
+	depth := 0
+	for k := i; k <= last && k < len(p.toks); k++ {
+		t := p.toks[k]
+		if t.kind != jsPunct {
+			continue
+		}
+		switch t.text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+		default:
+			if depth == 0 && t.text == text {
+				return k
+			}
+		}
+	}
+	return -1
+}
+
+// skipBalanced returns the index of the token after the bracket matching the one at k.
+func (p *jsParser) skipBalanced(k int) int {
+	depth := 0
+	for ; k < len(p.toks); k++ {
+		if p.toks[k].kind != jsPunct {
+			continue
+		}
+		switch p.toks[k].text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				return k + 1
+			}
+		}
+	}
+	return len(p.toks)
+}
+
+// angleEnd returns the index of the ">" closing the type parameters or arguments opened at k.
+func (p *jsParser) angleEnd(k, last int) int {
+	depth := 0
+	for ; k <= last && k < len(p.toks); k++ {
+		switch {
+		case p.isPunct(k, "<"):
+			depth++
+		case p.isPunct(k, ">"):
+			depth--
+		case p.isPunct(k, ">>"):
+			depth -= 2
+		case p.isPunct(k, "(") || p.isPunct(k, "[") || p.isPunct(k, "{"):
+			k = p.skipBalanced(k) - 1
+		}
+		if depth <= 0 {
+			return k
+		}
+	}
+	return last
+}
+
+// trimTerminator drops the semicolon or comma ending the statement at end.
+func (p *jsParser) trimTerminator(end int) int {
+	if p.isPunct(end, ";") || p.isPunct(end, ",") {
+		return end - 1
+	}
+	return end
+}
+
+// signature renders the tokens from a to b on one line, without the comments between them.
+func (p *jsParser) signature(a, b int) string {
+	var sb strings.Builder
+	for k := a; k <= b && k < len(p.toks); k++ {
+		if k > a && p.toks[k].start > p.toks[k-1].end {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(p.toks[k].text)
+	}
+	return foldSignature(sb.String(), false)
+}
+
+// finish marks exported the declarations of an export list, with the members they make
+// visible, and drops the bodiless
+// declarations of the functions and methods that also have an implementation in the
+// file, keeping one symbol per function.
+func (p *jsParser) finish() []domain.Symbol {
+	owners := make(map[string]bool)
+	for i, sym := range p.syms {
+		if sym.Parent == "" {
+			p.syms[i].Exported = sym.Exported || p.exportedNames[sym.Name]
+			owners[sym.Name] = p.syms[i].Exported
+		}
+	}
+	for i, sym := range p.syms {
+		if sym.Parent != "" {
+			p.syms[i].Exported = sym.Exported && owners[sym.Parent]
+		}
+	}
+	implemented := make(map[string]bool)
+	for i, sym := range p.syms {
+		if !p.bodiless[i] {
+			implemented[sym.Kind+" "+sym.Parent+"."+sym.Name] = true
+		}
+	}
+	var out []domain.Symbol
+	for i, sym := range p.syms {
+		if p.bodiless[i] && implemented[sym.Kind+" "+sym.Parent+"."+sym.Name] {
+			continue
+		}
+		out = append(out, sym)
+	}
+	return out
+}
+
+// jsDocText returns the text of a JSDoc comment, without its delimiters and leading asterisks.
+func jsDocText(comment string) string {
+	if comment == "" {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSuffix(strings.TrimPrefix(comment, "/**"), "*/"), "\n")
+	for i, l := range lines {
+		l = strings.TrimSpace(l)
+		if strings.HasPrefix(l, "*") {
+			l = strings.TrimPrefix(strings.TrimPrefix(l, "*"), " ")
+		}
+		lines[i] = l
+	}
+	return dedentDoc(strings.Join(lines, "\n"))
+}
+
+// jsTokenize splits src into tokens, dropping whitespace and comments; a JSDoc comment
+// is kept on the token that follows it. The expressions of template literals are
+// tokenized, the text around them is part of the literal's tokens.
+func jsTokenize(src []byte) []jsToken {
+	var (
+		toks   = make([]jsToken, 0, len(src)/6)
+		text   = string(src) // Token texts are slices of it
+		braces []bool        // Open braces, true for the "${" of a template literal
+		line   = 1
+		nl     = true
+		doc    string
+		i      int
+	)
+	if bytes.HasPrefix(src, []byte("#!")) {
+		for i < len(src) && src[i] != '\n' {
+			i++
+		}
+	}
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line, nl = line+1, true
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+			continue
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := len(src)
+			if n := bytes.Index(src[i+2:], []byte("*/")); n >= 0 {
+				end = i + 2 + n + 2
+			}
+			if comment := string(src[i:end]); strings.HasPrefix(comment, "/**") && comment != "/**/" {
+				doc = comment
+			}
+			line += bytes.Count(src[i:end], []byte("\n"))
+			i = end
+			continue
+		}
+
+		t := jsToken{start: i, line: line, nl: nl, doc: doc}
+		nl, doc = false, ""
+		t.kind = jsPunct
+		switch {
+		case c == '"' || c == '\'':
+			t.kind, i = jsLiteral, jsStringEnd(src, i)
+		case c == '`' || (c == '}' && len(braces) > 0 && braces[len(braces)-1]):
+			if c == '}' {
+				braces = braces[:len(braces)-1]
+			}
+			var open bool
+			i, open = jsTemplateEnd(src, i+1)
+			if open {
+				braces = append(braces, true)
+			}
+			t.kind = jsLiteral
+		case jsIdentByte(c) && (c < '0' || c > '9') || c == '#':
+			for i++; i < len(src) && jsIdentByte(src[i]); i++ {
+			}
+			t.kind = jsIdent
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			t.kind, i = jsLiteral, jsNumberEnd(src, i)
+		case c == '/' && jsRegexAllowed(toks):
+			t.kind, i = jsLiteral, jsRegexEnd(src, i)
+		default:
+			i += jsPunctLen(src[i:])
+			if c == '{' {
+				braces = append(braces, false)
+			} else if c == '}' && len(braces) > 0 {
+				braces = braces[:len(braces)-1]
+			}
+		}
+		t.end, t.text = i, text[t.start:i]
+		line += strings.Count(t.text, "\n")
+		t.endLine = line
+		toks = append(toks, t)
+	}
+	return toks
+}
+
+func jsIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '$' || c == '\\' || c >= 0x80
+}
+
+// jsStringEnd returns the offset after the string literal opening at src[i]. A string
+// left open ends at the end of its line, which also contains stray quotes in JSX text.
+func jsStringEnd(src []byte, i int) int {
+	q := src[i]
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case q:
+			return j + 1
+		case '\n':
+			return j
+		}
+	}
+	return len(src)
+}
+
+// jsTemplateEnd scans template literal text from j, returning the offset after its closing
+// backquote, or after a "${" that opens an expression (open).
+func jsTemplateEnd(src []byte, j int) (end int, open bool) {
+	for ; j < len(src); j++ {
+		switch {
+		case src[j] == '\\':
+			j++
+		case src[j] == '`':
+			return j + 1, false
+		case src[j] == '$' && j+1 < len(src) && src[j+1] == '{':
+			return j + 2, true
+		}
+	}
+	return len(src), false
+}
+
+func jsNumberEnd(src []byte, i int) int {
+	hex := bytes.HasPrefix(bytes.ToLower(src[i:min(i+2, len(src))]), []byte("0x"))
+	j := i + 1
+	for ; j < len(src); j++ {
+		c := src[j]
+		switch {
+		case jsIdentByte(c) || c == '.':
+		case (c == '+' || c == '-') && !hex && (src[j-1] == 'e' || src[j-1] == 'E'):
+		default:
+			return j
+		}
+	}
+	return j
+}
+
+// jsRegexAllowed reports whether a "/" after toks starts a regular expression rather than a division.
+func jsRegexAllowed(toks []jsToken) bool {
+	if len(toks) == 0 {
+		return true
+	}
+	prev := toks[len(toks)-1]
+	switch prev.kind {
+	case jsIdent:
+		switch prev.text {
+		case "return", "typeof", "instanceof", "in", "of", "new", "delete", "void", "throw", "case", "do", "else", "yield", "await":
+			return true
+		}
+		return false
+	case jsPunct:
+		switch prev.text {
+		case ")", "]", "}", "<": // "<" as in a JSX closing tag, </div>
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+func jsRegexEnd(src []byte, i int) int {
+	inClass := false
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case '\n':
+			return j
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				for j++; j < len(src) && jsIdentByte(src[j]); j++ { // Flags
+				}
+				return j
+			}
+		}
+	}
+	return len(src)
+}
+
+var (
+	jsPunct3 = []string{"...", "===", "!==", "**=", "<<=", ">>=", "&&=", "||=", "??="}
+	jsPunct2 = []string{"=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--", "+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "**", "<<", ">>"}
+)
+
+// jsPunctLen returns the length of the operator or bracket b starts with.
+func jsPunctLen(b []byte) int {
+	if len(b) >= 3 {
+		for _, op := range jsPunct3 {
+			if string(b[:3]) == op {
+				return 3
+			}
+		}
+	}
+	if len(b) >= 2 {
+		for _, op := range jsPunct2 {
+			if string(b[:2]) == op {
+				return 2
+			}
+		}
+	}
+	return 1
+}
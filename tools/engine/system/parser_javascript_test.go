@@ -0,0 +1,181 @@
+package system
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseJSLineRanges(t *testing.T) {
+	src := strings.Join([]string{
+		"/** Adds numbers. */",                    // 1
+		"export function add(a: number, b = 2) {", // 2
+		"  return a + b;",                         // 3
+		"}",                                       // 4
+		"",                                        // 5
+		"export class Store<T> extends Base {",    // 6
+		"  private items: T[] = [];",              // 7
+		"  get(key: string): T {",                 // 8
+		"    return this.items[0];",               // 9
+		"  }",                                     // 10
+		"  static #count = 0;",                    // 11
+		"}",                                       // 12
+		"",                                        // 13
+		"interface Options {",                     // 14
+		"  depth?: number;",                       // 15
+		"  [key: string]: unknown;",               // 16
+		"}",                                       // 17
+		"",                                        // 18
+		"export enum Color { Red, Green }",        // 19
+		"export type Pair<A, B = A> = [A, B];",    // 20
+		"export const LIMIT = 10, NAME = 'x';",    // 21
+		"export const double = (n: number) =>",    // 22
+		"  n * 2;",                                // 23
+		"module.exports.helper = function () {};", // 24
+	}, "\n")
+	want := []symbolLine{
+		{Name: "add", Kind: "function", Line: 2, LineEnd: 4, Exported: true},
+		{Name: "Store", Kind: "class", Line: 6, LineEnd: 12, Exported: true},
+		{Name: "items", Kind: "field", Parent: "Store", Line: 7, LineEnd: 7},
+		{Name: "get", Kind: "method", Parent: "Store", Line: 8, LineEnd: 10, Exported: true},
+		{Name: "#count", Kind: "field", Parent: "Store", Line: 11, LineEnd: 11},
+		{Name: "Options", Kind: "interface", Line: 14, LineEnd: 17},
+		{Name: "depth", Kind: "field", Parent: "Options", Line: 15, LineEnd: 15},
+		{Name: "Color", Kind: "enum", Line: 19, LineEnd: 19, Exported: true},
+		{Name: "Pair", Kind: "type", Line: 20, LineEnd: 20, Exported: true},
+		{Name: "LIMIT", Kind: "const", Line: 21, LineEnd: 21, Exported: true},
+		{Name: "NAME", Kind: "const", Line: 21, LineEnd: 21, Exported: true},
+		{Name: "double", Kind: "function", Line: 22, LineEnd: 23, Exported: true},
+		{Name: "helper", Kind: "function", Line: 24, LineEnd: 24, Exported: true},
+	}
+	if got := symbolLines(parseJS([]byte(src))); !reflect.DeepEqual(got, want) {
+		t.Errorf("symbols:\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseJSDetails(t *testing.T) {
+	src := strings.Join([]string{
+		`@Component({ selector: "app" })`,
+		`export class Widget {`,
+		`  /**`,
+		`   * The value.`,
+		`   * @default 0`,
+		`   */`,
+		`  @Input() value?: number;`,
+		``,
+		`  render(): string;`,
+		`  render(indent: number): string;`,
+		`  render(indent?: number): string {`,
+		`    return "";`,
+		`  }`,
+		`}`,
+		``,
+		`export function parse(text: string): Node;`,
+		`export function parse(text: string, strict: boolean): Node;`,
+		`export function parse(text: string, strict = false): Node {`,
+		`  return null;`,
+		`}`,
+	}, "\n")
+	symbols := parseJS([]byte(src))
+
+	tests := []struct {
+		parent, name   string
+		line           int
+		signature, doc string
+		decorators     []string
+	}{
+		{"", "Widget", 2, "export class Widget", "", []string{`Component({ selector: "app" })`}},
+		{"Widget", "value", 7, "value?: number", "The value.\n@default 0", []string{"Input()"}},
+		{"Widget", "render", 11, "render(indent?: number): string", "", nil},
+		{"", "parse", 18, "export function parse(text: string, strict = false): Node", "", nil},
+	}
+	for _, tt := range tests {
+		sym := findSymbol(t, symbols, tt.parent, tt.name)
+		if sym.Line != tt.line {
+			t.Errorf("%s at line %d, want %d (overloads are left out)", tt.name, sym.Line, tt.line)
+		}
+		if sym.Signature != tt.signature {
+			t.Errorf("%s signature = %q, want %q", tt.name, sym.Signature, tt.signature)
+		}
+		if sym.Docstring != tt.doc {
+			t.Errorf("%s docstring = %q, want %q", tt.name, sym.Docstring, tt.doc)
+		}
+		if !reflect.DeepEqual(sym.Decorators, tt.decorators) {
+			t.Errorf("%s decorators = %q, want %q", tt.name, sym.Decorators, tt.decorators)
+		}
+	}
+	if len(symbols) != 4 {
+		t.Errorf("got %d symbols, want 4 (overloads are left out): %+v", len(symbols), symbolLines(symbols))
+	}
+}
+
+func TestParseJSTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string // Names found
+	}{
+		{
+			name: "regex with braces",
+			src:  "export const re = /[{}]\\//g;\nfunction after() {}\n",
+			want: []string{"re", "after"},
+		},
+		{
+			name: "division is not a regex",
+			src:  "export const half = total / 2 / count;\nfunction after() {}\n",
+			want: []string{"half", "after"},
+		},
+		{
+			name: "regex after return",
+			src:  "function f(s) {\n  return /}/.test(s);\n}\nfunction g() {}\n",
+			want: []string{"f", "g"},
+		},
+		{
+			name: "template with nested braces",
+			src:  "export const t = `a ${ {b: 1}.b } }`;\nfunction after() {}\n",
+			want: []string{"t", "after"},
+		},
+		{
+			name: "JSX closing tag",
+			src:  "export function View() {\n  return <div>{x}</div>;\n}\nexport function Other() {}\n",
+			want: []string{"View", "Other"},
+		},
+		{
+			name: "unterminated string",
+			src:  "export const s = 'open\nfunction after() {}\n",
+			want: []string{"s", "after"},
+		},
+		{
+			name: "unterminated template",
+			src:  "function f() {}\nexport const t = `never closed\nfunction g() {}\n",
+			want: []string{"f", "t"},
+		},
+		{
+			name: "immediately invoked function",
+			src:  "export var Mod = function () { return {}; }();\nvar f = (function (x) { return x; });\n",
+			want: []string{"Mod", "f"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, s := range parseJS([]byte(tt.src)) {
+				got = append(got, s.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("symbols = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSFunctionValues(t *testing.T) {
+	src := "export var Mod = function () { return {}; }();\nvar f = (function (x) { return x; });\n"
+	symbols := parseJS([]byte(src))
+	if got := findSymbol(t, symbols, "", "Mod").Kind; got != "var" {
+		t.Errorf("an immediately invoked function is a %s, want var", got)
+	}
+	if got := findSymbol(t, symbols, "", "f").Kind; got != "function" {
+		t.Errorf("a parenthesized function expression is a %s, want function", got)
+	}
+}
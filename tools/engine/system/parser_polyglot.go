@@ -1,37 +1,44 @@
 package system
 
 import (
+	"context"
+	"log/slog"
 	"strings"
 
 	"github.com/Josepavese/asdp/engine/domain"
 )
 
-// PolyglotParser tries to use specialized parsers first, then falls back to Ctags.
-// Actually, Ctags is recursive, so it might duplicate.
-// Strategy:
-// 1. We want to use GoASTParser for .go files (better fidelity).
-// 2. We want to use Ctags for everything else.
-// BUT: Ctags -R walks everything.
-// Simple approach for V1:
-// Run GoASTParser.
-// Run CtagsParser.
-// Merge results, deduping by Name+File (not easy since ParseDir returns flattened symbols w/o file path in Symbol struct).
-// Wait, Symbol struct doesn't have FilePath yet? Let's check entities.
-// CodeModel Symbol has 'name', 'kind', 'parent'. Implicitly it belongs to the module.
-// If we want to support multi-file modules correctly, we might need to be careful.
-// Let's just append for now.
-
+// PolyglotParser combines the parsers of a module's languages: GoASTParser for Go
+// files, the enabled built-in parsers (Python, JavaScript/TypeScript) for theirs, and
+// ctags for everything else. Each one skips the files another handles, so their
+// symbols are simply appended.
 type PolyglotParser struct {
-	goParser    *GoASTParser
-	ctagsParser *CtagsParser
-	config      domain.Config
+	goParser      *GoASTParser
+	nativeParsers []nativeParser // The enabled built-in parsers of other languages
+	ctagsParser   *CtagsParser
+	config        domain.Config
+}
+
+// nativeParser is a built-in parser of a language other than Go, which ctags leaves alone.
+type nativeParser interface {
+	domain.ASTParser
+	domain.FileParser
+	handles(name string) bool
 }
 
 func NewPolyglotParser(config domain.Config) *PolyglotParser {
+	var natives []nativeParser
+	if config.Parsing.Python.Enabled {
+		natives = append(natives, NewPythonParser(config))
+	}
+	if config.Parsing.JavaScript.Enabled {
+		natives = append(natives, NewJSParser(config))
+	}
 	return &PolyglotParser{
-		goParser:    NewGoASTParser(config),
-		ctagsParser: NewCtagsParser(config),
-		config:      config,
+		goParser:      NewGoASTParser(config),
+		nativeParsers: natives,
+		ctagsParser:   NewCtagsParser(config),
+		config:        config,
 	}
 }
 
@@ -39,38 +46,85 @@ func (p *PolyglotParser) GetSymbolBody(root string, sym domain.Symbol) (string,
 	if strings.HasSuffix(sym.FilePath, ".go") {
 		return p.goParser.GetSymbolBody(root, sym)
 	}
+	for _, native := range p.nativeParsers {
+		if native.handles(sym.FilePath) {
+			return native.GetSymbolBody(root, sym)
+		}
+	}
 	return p.ctagsParser.GetSymbolBody(root, sym)
 }
 
-func (p *PolyglotParser) ParseDir(root string) ([]domain.Symbol, error) {
+func (p *PolyglotParser) ParseDir(ctx context.Context, root string) ([]domain.Symbol, error) {
 	var allSymbols []domain.Symbol
 
 	// 1. Try Go Native Parser
-	goSymbols, err := p.goParser.ParseDir(root)
+	goSymbols, err := p.goParser.ParseDir(ctx, root)
 	if err == nil {
 		allSymbols = append(allSymbols, goSymbols...)
+	} else {
+		slog.WarnContext(ctx, "Go parser failed", "root", root, "error", err)
 	}
 
-	// 2. Try Ctags Parser
-	// We need to tell ctags to EXCLUDE go files if we already parsed them?
-	// Or we just rely on Ctags for non-Go.
-	// ctags --exclude=*.go
-	// However, `CtagsParser` inside implementation hardcodes the command.
-	// Let's just run it. If Ctags returns Go symbols too, we might have duplicates.
-	// For this iteration, let's assume Ctags is mainly for "other" languages.
-	// We can update CtagsParser to accept excludes or we can just append.
+	// 1b. The built-in parsers of other languages, whose files ctags skips
+	for _, native := range p.nativeParsers {
+		nativeSymbols, err := native.ParseDir(ctx, root)
+		if err != nil {
+			slog.WarnContext(ctx, "native parser failed", "root", root, "parser", native.ParserVersion(), "error", err)
+			continue
+		}
+		allSymbols = append(allSymbols, nativeSymbols...)
+	}
 
-	ctagsSymbols, err := p.ctagsParser.ParseDir(root)
+	// 2. Ctags, for the files no parser above handles; a missing binary only loses those
+	ctagsSymbols, err := p.ctagsParser.ParseDir(ctx, root)
 	if err == nil {
-		// Deduping logic could go here.
-		// For now, let's just append.
-		// Actually, if Ctags is missing (err != nil), we just ignore it.
 		allSymbols = append(allSymbols, ctagsSymbols...)
 	} else {
-		// Ensure we don't return error if just ctags is missing, UNLESS go also failed.
-		// If we found 0 go symbols and ctags failed, maybe we return error?
-		// No, empty symbols is a valid result.
+		// Empty symbols is a valid result, so a missing ctags is not an error, only worth logging
+		slog.WarnContext(ctx, "ctags parser failed, non-Go symbols are skipped", "root", root, "error", err)
+	}
+
+	// The parsers tolerate failures, but a cancelled request must not look like an empty module
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
 	return allSymbols, nil
 }
+
+func (p *PolyglotParser) ParserVersion() string {
+	versions := []string{p.goParser.ParserVersion()}
+	for _, native := range p.nativeParsers {
+		versions = append(versions, native.ParserVersion())
+	}
+	versions = append(versions, p.ctagsParser.ParserVersion())
+	for _, v := range versions {
+		if v == "" {
+			return ""
+		}
+	}
+	return strings.Join(versions, "+")
+}
+
+// ParseFiles is ParseDir restricted to files (slash-separated, relative to root).
+func (p *PolyglotParser) ParseFiles(ctx context.Context, root string, files []string) ([]domain.Symbol, error) {
+	symbols, err := p.goParser.ParseFiles(ctx, root, files)
+	if err != nil {
+		return nil, err
+	}
+	for _, native := range p.nativeParsers {
+		nativeSymbols, err := native.ParseFiles(ctx, root, files)
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, nativeSymbols...)
+	}
+	ctagsSymbols, err := p.ctagsParser.ParseFiles(ctx, root, files)
+	if err != nil {
+		slog.WarnContext(ctx, "ctags parser failed, non-Go symbols are skipped", "root", root, "error", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return append(symbols, ctagsSymbols...), nil
+}
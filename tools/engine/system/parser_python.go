@@ -0,0 +1,306 @@
+package system
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// PythonParser extracts the classes, functions and methods of Python sources, with their
+// decorators and docstrings, without ctags. It reads logical lines and follows their
+// indentation the way the interpreter does; functions nested in functions are left out.
+type PythonParser struct {
+	sourceParser
+}
+
+// pythonParserVersion changes whenever parsePython would produce different symbols for the same file.
+const pythonParserVersion = "py/3"
+
+func NewPythonParser(config domain.Config) *PythonParser {
+	return &PythonParser{sourceParser{
+		language:       "Python",
+		version:        pythonParserVersion,
+		extensions:     config.Parsing.Python.Extensions,
+		ignorePatterns: config.IgnorePatterns,
+		parse:          parsePython,
+	}}
+}
+
+var (
+	pyDefHeader   = regexp.MustCompile(`^(?:async\s+)?def\s+([\pL_][\pL\pN_]*)`)
+	pyClassHeader = regexp.MustCompile(`^class\s+([\pL_][\pL\pN_]*)`)
+)
+
+// parsePython extracts the symbols of one Python file.
+func parsePython(src []byte) []domain.Symbol {
+	type scope struct {
+		indent int
+		sym    int    // Index of its symbol, -1 for a local function or class
+		class  string // Dotted name of a class scope
+	}
+	var (
+		symbols    []domain.Symbol
+		stack      []scope
+		decorators []string
+	)
+
+	lines := pyLogicalLines(src)
+	for k, l := range lines {
+		for len(stack) > 0 && l.indent <= stack[len(stack)-1].indent {
+			stack = stack[:len(stack)-1]
+		}
+		for _, s := range stack {
+			if s.sym >= 0 {
+				symbols[s.sym].LineEnd = l.end
+			}
+		}
+
+		code, mask := l.trimmed()
+		if strings.HasPrefix(mask, "@") {
+			decorators = append(decorators, foldSignature(code[1:], false))
+			continue
+		}
+		pending := decorators
+		decorators = nil
+
+		kind, name, nameEnd := pyHeader(mask)
+		if kind == "" {
+			continue
+		}
+		colon := pyHeaderColon(mask, nameEnd)
+		if colon < 0 {
+			continue
+		}
+		var enclosing *scope
+		if len(stack) > 0 {
+			enclosing = &stack[len(stack)-1]
+		}
+		if enclosing != nil && enclosing.class == "" {
+			// Local to a function: not part of the module's API, and neither is its body
+			stack = append(stack, scope{indent: l.indent, sym: -1})
+			continue
+		}
+
+		sym := domain.Symbol{
+			Name:       name,
+			Kind:       kind,
+			Exported:   pyExported(name),
+			Line:       l.start,
+			LineEnd:    l.end,
+			Signature:  foldSignature(code[:colon], false),
+			Decorators: pending,
+		}
+		if enclosing != nil {
+			sym.Parent = enclosing.class
+			sym.Exported = sym.Exported && symbols[enclosing.sym].Exported
+			if kind == "function" {
+				sym.Kind = "method"
+			}
+		}
+		if strings.TrimSpace(code[colon+1:]) != "" {
+			sym.Docstring = pyDocstring(code[colon+1:], mask[colon+1:]) // A one-line body
+		} else if k+1 < len(lines) && lines[k+1].indent > l.indent {
+			sym.Docstring = pyDocstring(lines[k+1].trimmed())
+		}
+		symbols = append(symbols, sym)
+
+		class := ""
+		if kind == "class" {
+			class = name
+			if sym.Parent != "" {
+				class = sym.Parent + "." + name
+			}
+		}
+		stack = append(stack, scope{indent: l.indent, sym: len(symbols) - 1, class: class})
+	}
+	return symbols
+}
+
+// pyExported follows the convention that a leading underscore marks a private name; dunder
+// methods such as __init__ are part of a class's interface.
+func pyExported(name string) bool {
+	return !strings.HasPrefix(name, "_") || (strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__"))
+}
+
+// pyHeader recognizes the header of a def or class statement.
+func pyHeader(mask string) (kind, name string, nameEnd int) {
+	if m := pyDefHeader.FindStringSubmatchIndex(mask); m != nil {
+		return "function", mask[m[2]:m[3]], m[3]
+	}
+	if m := pyClassHeader.FindStringSubmatchIndex(mask); m != nil {
+		return "class", mask[m[2]:m[3]], m[3]
+	}
+	return "", "", 0
+}
+
+// pyHeaderColon returns the offset of the colon that ends a def or class header, or -1.
+func pyHeaderColon(mask string, from int) int {
+	depth := 0
+	for i := from; i < len(mask); i++ {
+		switch mask[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ':':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// pyDocstring returns the docstring of a body whose first statement is code, if that
+// statement is a lone string literal.
+func pyDocstring(code, mask string) string {
+	lead := len(code) - len(strings.TrimLeft(code, " \t"))
+	code, mask = code[lead:], mask[lead:]
+	i := 0
+	for i < len(code) && i < 2 && strings.IndexByte("rRuUbBfF", code[i]) >= 0 {
+		i++
+	}
+	if i >= len(code) || (code[i] != '"' && code[i] != '\'') {
+		return ""
+	}
+	end := pyStringEnd([]byte(code), i)
+	if strings.TrimSpace(mask[end:]) != "" {
+		return ""
+	}
+	quotes := 1
+	if strings.HasPrefix(code[i:], strings.Repeat(code[i:i+1], 3)) {
+		quotes = 3
+	}
+	if end-quotes < i+quotes {
+		return "" // Unterminated
+	}
+	return dedentDoc(code[i+quotes : end-quotes])
+}
+
+// pyLine is a logical line of Python: physical lines joined by open brackets or
+// backslashes, with comments cut.
+type pyLine struct {
+	start, end int    // Physical lines, 1-based
+	indent     int    // Columns before the first token, tabs stopping at multiples of 8
+	code       string // The source text
+	mask       string // code with string literals blanked out, to find brackets and colons in
+}
+
+// trimmed returns code and mask without their indentation, which they share.
+func (l pyLine) trimmed() (string, string) {
+	lead := len(l.code) - len(strings.TrimLeft(l.code, " \t\f"))
+	return l.code[lead:], l.mask[lead:]
+}
+
+// pyLogicalLines splits src into its non-blank logical lines.
+func pyLogicalLines(src []byte) []pyLine {
+	var (
+		lines      []pyLine
+		code, mask []byte
+		line       = 1
+		start      int
+		indent     int
+		depth      int
+		atStart    = true
+	)
+	flush := func() {
+		if len(bytes.TrimSpace(code)) > 0 {
+			lines = append(lines, pyLine{start: start, end: line, indent: indent, code: string(code), mask: string(mask)})
+		}
+		code, mask = code[:0], mask[:0]
+		depth, atStart = 0, true
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		if atStart {
+			indent = 0
+			for ; i < len(src) && (src[i] == ' ' || src[i] == '\t' || src[i] == '\f'); i++ {
+				if src[i] == '\t' {
+					indent = indent/8*8 + 8
+				} else if src[i] == ' ' {
+					indent++
+				}
+			}
+			start, atStart = line, false
+			continue
+		}
+		switch {
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '\\' && (bytes.HasPrefix(src[i+1:], []byte("\n")) || bytes.HasPrefix(src[i+1:], []byte("\r\n"))):
+			i = bytes.IndexByte(src[i:], '\n') + i + 1
+			for i < len(src) && (src[i] == ' ' || src[i] == '\t') {
+				i++
+			}
+			line++
+			if n := len(code); n > 0 && code[n-1] != ' ' && code[n-1] != '\t' {
+				code, mask = append(code, ' '), append(mask, ' ')
+			}
+		case c == '\n':
+			if depth > 0 {
+				code, mask = append(code, '\n'), append(mask, '\n')
+			} else {
+				flush()
+			}
+			line++
+			i++
+		case c == '"' || c == '\'':
+			end := pyStringEnd(src, i)
+			for _, b := range src[i:end] {
+				code = append(code, b)
+				if b == '\n' {
+					mask = append(mask, '\n')
+					line++
+				} else {
+					mask = append(mask, '"')
+				}
+			}
+			i = end
+		default:
+			switch c {
+			case '(', '[', '{':
+				depth++
+			case ')', ']', '}':
+				if depth > 0 {
+					depth--
+				}
+			}
+			code, mask = append(code, c), append(mask, c)
+			i++
+		}
+	}
+	flush()
+	return lines
+}
+
+// pyStringEnd returns the offset after the string literal opening at src[i]. A short
+// string left open ends at the end of its line.
+func pyStringEnd(src []byte, i int) int {
+	q := src[i]
+	if bytes.HasPrefix(src[i:], []byte{q, q, q}) {
+		for j := i + 3; j < len(src); j++ {
+			if src[j] == '\\' {
+				j++
+			} else if bytes.HasPrefix(src[j:], []byte{q, q, q}) {
+				return j + 3
+			}
+		}
+		return len(src)
+	}
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case q:
+			return j + 1
+		case '\n':
+			return j
+		}
+	}
+	return len(src)
+}
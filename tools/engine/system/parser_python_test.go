@@ -0,0 +1,165 @@
+package system
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// symbolLine is the part of a symbol the parser tests compare.
+type symbolLine struct {
+	Name, Kind, Parent string
+	Line, LineEnd      int
+	Exported           bool
+}
+
+func symbolLines(symbols []domain.Symbol) []symbolLine {
+	out := make([]symbolLine, len(symbols))
+	for i, s := range symbols {
+		out[i] = symbolLine{Name: s.Name, Kind: s.Kind, Parent: s.Parent, Line: s.Line, LineEnd: s.LineEnd, Exported: s.Exported}
+	}
+	return out
+}
+
+func findSymbol(t *testing.T, symbols []domain.Symbol, parent, name string) domain.Symbol {
+	t.Helper()
+	for _, s := range symbols {
+		if s.Parent == parent && s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("no symbol %s.%s among %+v", parent, name, symbolLines(symbols))
+	return domain.Symbol{}
+}
+
+func TestParsePythonLineRanges(t *testing.T) {
+	src := strings.Join([]string{
+		"import os",                   // 1
+		"",                            // 2
+		"class Store(Base):",          // 3
+		"    class Meta:",             // 4
+		"        pass",                // 5
+		"",                            // 6
+		"    def get(self, key):",     // 7
+		"        def local():",        // 8
+		"            pass",            // 9
+		"        return key",          // 10
+		"",                            // 11
+		"    async def _load(self,",   // 12
+		"                    path):",  // 13
+		"        pass",                // 14
+		"",                            // 15
+		"def __getattr__(name): pass", // 16
+		"",                            // 17
+		"def _helper():",              // 18
+		"    x = (1,",                 // 19
+		"         2)",                 // 20
+		"# trailing comment",          // 21
+	}, "\n")
+	want := []symbolLine{
+		{Name: "Store", Kind: "class", Line: 3, LineEnd: 14, Exported: true},
+		{Name: "Meta", Kind: "class", Parent: "Store", Line: 4, LineEnd: 5, Exported: true},
+		{Name: "get", Kind: "method", Parent: "Store", Line: 7, LineEnd: 10, Exported: true},
+		{Name: "_load", Kind: "method", Parent: "Store", Line: 12, LineEnd: 14},
+		{Name: "__getattr__", Kind: "function", Line: 16, LineEnd: 16, Exported: true},
+		{Name: "_helper", Kind: "function", Line: 18, LineEnd: 20},
+	}
+	if got := symbolLines(parsePython([]byte(src))); !reflect.DeepEqual(got, want) {
+		t.Errorf("symbols:\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParsePythonDetails(t *testing.T) {
+	src := strings.Join([]string{
+		`class Cache:`,
+		`    """An LRU cache.`,
+		``,
+		`    Keys are strings.`,
+		`    """`,
+		``,
+		`    @staticmethod`,
+		`    @functools.lru_cache(maxsize=None)`,
+		`    def build(size: int = 8) -> "Cache":`,
+		`        '''Builds one.'''`,
+		``,
+		`    def peek(self): "Looks without touching."`,
+		``,
+		`def parse(text,`,
+		`          strict=False) -> dict:`,
+		`    """Parses text: "(a)" and {b}."""`,
+		``,
+		`def raw(): return r"\d"`,
+	}, "\n")
+	symbols := parsePython([]byte(src))
+
+	tests := []struct {
+		parent, name   string
+		signature, doc string
+		decorators     []string
+	}{
+		{"", "Cache", "class Cache", "An LRU cache.\n\nKeys are strings.", nil},
+		{"Cache", "build", `def build(size: int = 8) -> "Cache"`, "Builds one.", []string{"staticmethod", "functools.lru_cache(maxsize=None)"}},
+		{"Cache", "peek", "def peek(self)", "Looks without touching.", nil},
+		{"", "parse", "def parse(text, strict=False) -> dict", `Parses text: "(a)" and {b}.`, nil},
+		{"", "raw", "def raw()", "", nil},
+	}
+	for _, tt := range tests {
+		sym := findSymbol(t, symbols, tt.parent, tt.name)
+		if sym.Signature != tt.signature {
+			t.Errorf("%s signature = %q, want %q", tt.name, sym.Signature, tt.signature)
+		}
+		if sym.Docstring != tt.doc {
+			t.Errorf("%s docstring = %q, want %q", tt.name, sym.Docstring, tt.doc)
+		}
+		if !reflect.DeepEqual(sym.Decorators, tt.decorators) {
+			t.Errorf("%s decorators = %q, want %q", tt.name, sym.Decorators, tt.decorators)
+		}
+	}
+}
+
+func TestParsePythonStrings(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string // Names found
+	}{
+		{
+			name: "def inside a string",
+			src:  "x = '''\ndef fake():\n    pass\n'''\ndef real(): pass\n",
+			want: []string{"real"},
+		},
+		{
+			name: "colon and brackets in a default",
+			src:  "def f(a=':)', b='['):\n    pass\ndef g(): pass\n",
+			want: []string{"f", "g"},
+		},
+		{
+			name: "unterminated short string",
+			src:  "x = 'open\ndef after(): pass\n",
+			want: []string{"after"},
+		},
+		{
+			name: "unterminated docstring",
+			src:  "def f():\n    \"\"\"never closed\n\ndef g(): pass\n",
+			want: []string{"f"},
+		},
+		{
+			name: "backslash continuation",
+			src:  "def f(a, \\\n      b): pass\n",
+			want: []string{"f"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, s := range parsePython([]byte(tt.src)) {
+				got = append(got, s.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("symbols = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
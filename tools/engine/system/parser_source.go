@@ -0,0 +1,156 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// sourceParser is the part the built-in parsers of non-Go languages share: walking a
+// module for the files with their extensions, and mapping symbols to their lines.
+// Each language only brings parse, which extracts the symbols of one file's source.
+type sourceParser struct {
+	language       string
+	version        string
+	extensions     []string
+	ignorePatterns []string
+	parse          func(src []byte) []domain.Symbol
+}
+
+// handles reports whether name has one of the parser's extensions.
+func (p *sourceParser) handles(name string) bool {
+	return hasExtension(name, p.extensions)
+}
+
+func hasExtension(name string, extensions []string) bool {
+	name = strings.ToLower(name)
+	for _, ext := range extensions {
+		if strings.HasSuffix(name, strings.ToLower(ext)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *sourceParser) GetSymbolBody(root string, sym domain.Symbol) (string, error) {
+	if sym.FilePath == "" {
+		return "", fmt.Errorf("symbol has no file path")
+	}
+
+	fullPath := filepath.Join(root, sym.FilePath)
+	data, err := os.ReadFile(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %s: %w", fullPath, err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if sym.Line <= 0 || sym.Line > len(lines) {
+		return "", fmt.Errorf("invalid start line %d", sym.Line)
+	}
+
+	end := sym.LineEnd
+	if end < sym.Line || end > len(lines) {
+		end = sym.Line
+	}
+	return strings.Join(lines[sym.Line-1:end], "\n"), nil
+}
+
+// ParseDir parses the files of the module at root the parser handles, walking it like
+// GoASTParser.ParseDir: ignored directories and sub-modules are skipped.
+func (p *sourceParser) ParseDir(ctx context.Context, root string) ([]domain.Symbol, error) {
+	var symbols []domain.Symbol
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == root {
+				return nil
+			}
+			if matchesIgnorePattern(p.ignorePatterns, d.Name()) || isModuleBoundary(path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !p.handles(d.Name()) || !d.Type().IsRegular() {
+			return nil
+		}
+		symbols = append(symbols, p.parseFile(ctx, root, path)...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s directory %s: %w", p.language, root, err)
+	}
+	return symbols, nil
+}
+
+func (p *sourceParser) ParserVersion() string {
+	return p.version
+}
+
+// ParseFiles parses the given files (slash-separated, relative to root), skipping the
+// ones ParseDir would skip. Files that no longer exist are ignored.
+func (p *sourceParser) ParseFiles(ctx context.Context, root string, files []string) ([]domain.Symbol, error) {
+	var symbols []domain.Symbol
+	for _, rel := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !p.handles(path.Base(rel)) || inIgnoredDir(p.ignorePatterns, rel) {
+			continue
+		}
+		full := filepath.Join(root, filepath.FromSlash(rel))
+		if info, err := os.Stat(full); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		symbols = append(symbols, p.parseFile(ctx, root, full)...)
+	}
+	return symbols, nil
+}
+
+// parseFile extracts the symbols of one file. A file that cannot be read yields none.
+func (p *sourceParser) parseFile(ctx context.Context, root, path string) []domain.Symbol {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		slog.WarnContext(ctx, "skipping unreadable source file", "language", p.language, "file", path, "error", err)
+		return nil
+	}
+	relPath, _ := filepath.Rel(root, path)
+	symbols := p.parse(src)
+	for i := range symbols {
+		symbols[i].FilePath = relPath
+	}
+	return symbols
+}
+
+// dedentDoc returns the text of a docstring or doc comment with its first line trimmed and
+// the common indentation of the others removed, like Python's inspect.cleandoc.
+func dedentDoc(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\t", "        "), "\n")
+	margin := -1
+	for _, l := range lines[1:] {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		if indent := len(l) - len(strings.TrimLeft(l, " ")); margin < 0 || indent < margin {
+			margin = indent
+		}
+	}
+	lines[0] = strings.TrimSpace(lines[0])
+	for i := 1; i < len(lines); i++ {
+		if margin > 0 && len(lines[i]) >= margin {
+			lines[i] = lines[i][margin:]
+		}
+		lines[i] = strings.TrimRight(lines[i], " \r")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
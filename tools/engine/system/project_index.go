@@ -0,0 +1,200 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+const (
+	projectIndexFile    = "project-index.json"
+	projectIndexVersion = 1 // Bump when the persisted layout changes
+)
+
+// ProjectIndex remembers what was derived from project files (the content of ASDP
+// artifacts, per-file content hashes, per-module source hashes) together with the
+// FileStamp it was derived from, so a long-lived server only re-reads what changed.
+// It is persisted under <root>/<System.CacheDir> so the next server starts warm.
+type ProjectIndex struct {
+	mu    sync.Mutex
+	path  string // Empty when the index is not persisted
+	data  projectIndexData
+	dirty bool
+}
+
+type projectIndexData struct {
+	Version int                     `json:"version"`
+	Files   map[string]*IndexedFile `json:"files"` // By absolute path
+	Dirs    map[string]*indexedDir  `json:"dirs"`  // HashDir results, by absolute path
+}
+
+// IndexedFile is what is known about one version of a file.
+type IndexedFile struct {
+	Stamp   FileStamp `json:"stamp"`
+	Checked int64     `json:"checked"`           // When Stamp was taken (Unix ns)
+	Hash    string    `json:"sha256,omitempty"`  // Of the content
+	Content []byte    `json:"content,omitempty"` // ASDP artifacts only
+}
+
+type indexedDir struct {
+	Files []string `json:"files"` // Relative to the directory, sorted: the inputs of Hash
+	Hash  string   `json:"hash"`
+}
+
+// NewProjectIndex returns an empty, in-memory only index.
+func NewProjectIndex() *ProjectIndex {
+	return &ProjectIndex{data: emptyIndexData()}
+}
+
+// OpenProjectIndex loads the index persisted for root. A missing, unreadable or
+// outdated file yields an empty index, which Save will then replace.
+func OpenProjectIndex(root string, config domain.SystemConfig) *ProjectIndex {
+	ix := &ProjectIndex{path: filepath.Join(root, config.CacheDir, projectIndexFile), data: emptyIndexData()}
+
+	raw, err := os.ReadFile(ix.path)
+	if err != nil {
+		return ix
+	}
+	var data projectIndexData
+	if err := json.Unmarshal(raw, &data); err != nil || data.Version != projectIndexVersion {
+		return ix
+	}
+	if data.Files != nil {
+		ix.data.Files = data.Files
+	}
+	if data.Dirs != nil {
+		ix.data.Dirs = data.Dirs
+	}
+	return ix
+}
+
+func emptyIndexData() projectIndexData {
+	return projectIndexData{
+		Version: projectIndexVersion,
+		Files:   make(map[string]*IndexedFile),
+		Dirs:    make(map[string]*indexedDir),
+	}
+}
+
+// Save persists the index if it changed since it was loaded or last saved.
+func (ix *ProjectIndex) Save() error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if ix.path == "" || !ix.dirty {
+		return nil
+	}
+
+	// Entries whose file disappeared are dropped here rather than on every lookup
+	for path := range ix.data.Files {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			delete(ix.data.Files, path)
+		}
+	}
+	for dir := range ix.data.Dirs {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			delete(ix.data.Dirs, dir)
+		}
+	}
+
+	raw, err := json.Marshal(ix.data)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(ix.path), 0755); err != nil {
+		return err
+	}
+	// Write then rename, so a concurrent server never loads a torn file
+	tmp := fmt.Sprintf("%s.%d.tmp", ix.path, os.Getpid())
+	if err := os.WriteFile(tmp, raw, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, ix.path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	ix.dirty = false
+	return nil
+}
+
+// lookup returns the entry of path if it was recorded for this very stamp and the stamp can be trusted.
+func (ix *ProjectIndex) lookup(path string, stamp FileStamp) (*IndexedFile, bool) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	f, ok := ix.data.Files[path]
+	if !ok || f.Stamp != stamp || !f.Stamp.trusted(f.Checked) {
+		return nil, false
+	}
+	return f, true
+}
+
+// store records f for path, keeping the content or hash already known for the same stamp.
+func (ix *ProjectIndex) store(path string, f *IndexedFile) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if old, ok := ix.data.Files[path]; ok && old.Stamp == f.Stamp {
+		if f.Content == nil {
+			f.Content = old.Content
+		}
+		if f.Hash == "" {
+			f.Hash = old.Hash
+		}
+	}
+	ix.data.Files[path] = f
+	ix.dirty = true
+}
+
+func (ix *ProjectIndex) forget(path string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if _, ok := ix.data.Files[path]; ok {
+		delete(ix.data.Files, path)
+		ix.dirty = true
+	}
+}
+
+// dirHash returns the hash recorded for dir if it was computed from exactly these
+// files (relative, sorted) and none of them changed since.
+func (ix *ProjectIndex) dirHash(dir string, files []string, stamps []FileStamp) (string, bool) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	d, ok := ix.data.Dirs[dir]
+	if !ok || len(d.Files) != len(files) {
+		return "", false
+	}
+	for i, rel := range files {
+		if d.Files[i] != rel {
+			return "", false
+		}
+		f, ok := ix.data.Files[filepath.Join(dir, rel)]
+		if !ok || f.Stamp != stamps[i] || !f.Stamp.trusted(f.Checked) {
+			return "", false
+		}
+	}
+	return d.Hash, true
+}
+
+// fileHashes returns the content hash of each of the files (relative to dir), if all are known.
+func (ix *ProjectIndex) fileHashes(dir string, files []string) (map[string]string, bool) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	hashes := make(map[string]string, len(files))
+	for _, rel := range files {
+		f, ok := ix.data.Files[filepath.Join(dir, rel)]
+		if !ok || f.Hash == "" {
+			return nil, false
+		}
+		hashes[filepath.ToSlash(rel)] = f.Hash
+	}
+	return hashes, true
+}
+
+func (ix *ProjectIndex) storeDirHash(dir string, files []string, hash string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.data.Dirs[dir] = &indexedDir{Files: files, Hash: hash}
+	ix.dirty = true
+}
@@ -0,0 +1,246 @@
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// writeStamped writes content to path and sets its modification time.
+func writeStamped(t *testing.T, path, content string, mtime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// replaceStamped renames a new file with content and mtime over path.
+func replaceStamped(t *testing.T, path, content string, mtime time.Time) {
+	t.Helper()
+	tmp := path + ".tmp"
+	writeStamped(t, tmp, content, mtime)
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileStampTrusted(t *testing.T) {
+	checked := time.Now().UnixNano()
+	tests := []struct {
+		age  time.Duration // Of the modification at checked
+		want bool
+	}{
+		{time.Hour, true},
+		{racyWindow + time.Millisecond, true},
+		{racyWindow, false},
+		{0, false},
+		{-time.Second, false}, // Modified after it was read
+	}
+	for _, tt := range tests {
+		s := FileStamp{ModTime: checked - int64(tt.age), Size: 1}
+		if got := s.trusted(checked); got != tt.want {
+			t.Errorf("trusted %v after modification = %v, want %v", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestCachedFileSystemReadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "codespec.md")
+	past := time.Now().Add(-time.Hour)
+	ix := NewProjectIndex()
+	fs := NewCachedFileSystem(ix)
+
+	read := func(step, want string) {
+		t.Helper()
+		data, err := fs.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v", step, err)
+		}
+		if string(data) != want {
+			t.Errorf("%s: read %q, want %q", step, data, want)
+		}
+	}
+
+	writeStamped(t, path, "first", past)
+	read("first read", "first")
+
+	// An unchanged, trusted stamp is served from the index
+	ix.data.Files[path].Content = []byte("index")
+	read("unchanged", "index")
+
+	writeStamped(t, path, "second!", past)
+	read("rewritten with another size", "second!")
+
+	// Same size and modification time, but another file: only the inode tells
+	if ix.data.Files[path].Stamp.Inode != 0 {
+		replaceStamped(t, path, "third!!", past)
+		read("renamed over", "third!!")
+	}
+
+	// A file modified right before it was read is read again, even with the same stamp
+	recent := time.Now()
+	writeStamped(t, path, "racy 1", recent)
+	read("racy read", "racy 1")
+	writeStamped(t, path, "racy 2", recent)
+	read("racy rewrite", "racy 2")
+
+	writeStamped(t, path, "fourth", past)
+	read("before a write", "fourth")
+	if err := fs.WriteFile(path, []byte("fifth")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ix.data.Files[path]; ok {
+		t.Error("WriteFile kept the index entry")
+	}
+	read("written", "fifth")
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.ReadFile(path); err == nil {
+		t.Error("removed file read from the index")
+	}
+
+	// Other files are never indexed
+	source := filepath.Join(dir, "main.go")
+	writeStamped(t, source, "package main", past)
+	if _, err := fs.ReadFile(source); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ix.data.Files[source]; ok {
+		t.Error("source file indexed")
+	}
+}
+
+func TestIndexedHashDir(t *testing.T) {
+	config := domain.DefaultConfig().Hasher
+	dir := t.TempDir()
+	past := time.Now().Add(-time.Hour)
+	writeStamped(t, filepath.Join(dir, "a.go"), "package a", past)
+	writeStamped(t, filepath.Join(dir, "b.go"), "package a // b", past)
+
+	ix := NewProjectIndex()
+	indexed := NewIndexedContentHasher(config, ix)
+	plain := NewSHA256ContentHasher(config)
+	ctx := context.Background()
+
+	check := func(step string) {
+		t.Helper()
+		got, manifest, err := indexed.ManifestDir(ctx, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, wantManifest, err := plain.ManifestDir(ctx, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s: hash %s, want %s", step, got, want)
+		}
+		if len(manifest) != len(wantManifest) {
+			t.Errorf("%s: manifest %v, want %v", step, manifest, wantManifest)
+		}
+		for rel, hash := range wantManifest {
+			if manifest[rel] != hash {
+				t.Errorf("%s: manifest[%s] = %s, want %s", step, rel, manifest[rel], hash)
+			}
+		}
+	}
+
+	check("first hash")
+
+	// While no file changed, the recorded hash is returned as is
+	recorded := ix.data.Dirs[dir].Hash
+	ix.data.Dirs[dir].Hash = "index"
+	if got, _ := indexed.HashDir(ctx, dir); got != "index" {
+		t.Errorf("unchanged dir hashed to %s, want the recorded hash", got)
+	}
+	ix.data.Dirs[dir].Hash = recorded
+
+	writeStamped(t, filepath.Join(dir, "a.go"), "package a // changed", past)
+	check("file rewritten")
+
+	if stamp := ix.data.Files[filepath.Join(dir, "a.go")].Stamp; stamp.Inode != 0 {
+		replaceStamped(t, filepath.Join(dir, "a.go"), "package a // CHANGED", time.Unix(0, stamp.ModTime))
+		check("file renamed over")
+	}
+
+	if err := os.Remove(filepath.Join(dir, "b.go")); err != nil {
+		t.Fatal(err)
+	}
+	check("file removed")
+
+	writeStamped(t, filepath.Join(dir, "c.go"), "package a // c", past)
+	check("file added")
+}
+
+func TestProjectIndexPersists(t *testing.T) {
+	root := t.TempDir()
+	config := domain.DefaultConfig()
+	past := time.Now().Add(-time.Hour)
+	spec, gone := filepath.Join(root, "codespec.md"), filepath.Join(root, "codemodel.md")
+	writeStamped(t, spec, "spec", past)
+	writeStamped(t, gone, "model", past)
+	writeStamped(t, filepath.Join(root, "a.go"), "package a", past)
+
+	ix := OpenProjectIndex(root, config.System)
+	fs := NewCachedFileSystem(ix)
+	ctx := context.Background()
+	for _, path := range []string{spec, gone} {
+		if _, err := fs.ReadFile(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hash, err := NewIndexedContentHasher(config.Hasher, ix).HashDir(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+	if err := ix.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := OpenProjectIndex(root, config.System)
+	if f, ok := reopened.data.Files[spec]; !ok || string(f.Content) != "spec" {
+		t.Errorf("reloaded entry of codespec.md = %+v", f)
+	}
+	if _, ok := reopened.data.Files[gone]; ok {
+		t.Error("entry of a removed file was saved")
+	}
+	if d, ok := reopened.data.Dirs[root]; !ok || d.Hash != hash {
+		t.Errorf("reloaded dir hash = %+v, want %s", d, hash)
+	}
+
+	// The reloaded index serves reads and hashes until the files change
+	reopened.data.Files[spec].Content = []byte("index")
+	reopened.data.Dirs[root].Hash = "index"
+	if data, _ := NewCachedFileSystem(reopened).ReadFile(spec); string(data) != "index" {
+		t.Errorf("reloaded index not used: read %q", data)
+	}
+	if got, _ := NewIndexedContentHasher(config.Hasher, reopened).HashDir(ctx, root); got != "index" {
+		t.Errorf("reloaded dir hash not used: hashed to %s", got)
+	}
+	writeStamped(t, spec, "spec 2", past)
+	if data, _ := NewCachedFileSystem(reopened).ReadFile(spec); string(data) != "spec 2" {
+		t.Errorf("after a rewrite: read %q", data)
+	}
+
+	// An index of another layout version is discarded
+	path := filepath.Join(root, config.System.CacheDir, projectIndexFile)
+	if err := os.WriteFile(path, []byte(`{"version":0,"files":{"x":{}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if old := OpenProjectIndex(root, config.System); len(old.data.Files) != 0 {
+		t.Errorf("outdated index loaded: %+v", old.data.Files)
+	}
+}
@@ -0,0 +1,28 @@
+package system
+
+import (
+	"os"
+	"time"
+)
+
+// racyWindow is how long after a file was stamped its stamp is not trusted: a file rewritten
+// with the same size within the filesystem's timestamp granularity keeps its modification time.
+const racyWindow = 2 * time.Second
+
+// FileStamp identifies a version of a file without reading it. Anything derived from the
+// file is reused only while its stamp is unchanged.
+type FileStamp struct {
+	ModTime int64  `json:"mtime"` // Unix nanoseconds
+	Size    int64  `json:"size"`
+	Inode   uint64 `json:"inode,omitempty"` // 0 on platforms without inodes
+}
+
+func stampOf(info os.FileInfo) FileStamp {
+	return FileStamp{ModTime: info.ModTime().UnixNano(), Size: info.Size(), Inode: inodeOf(info)}
+}
+
+// trusted reports whether s, taken at checkedAt (Unix ns), can stand for the content
+// read at that time: the file was not modified within racyWindow of being read.
+func (s FileStamp) trusted(checkedAt int64) bool {
+	return s.ModTime < checkedAt-int64(racyWindow)
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package system
+
+import (
+	"os"
+	"syscall"
+)
+
+// inodeOf catches a file replaced by another one (e.g. an atomic rename by an editor)
+// that happens to have the same size and modification time.
+func inodeOf(info os.FileInfo) uint64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Ino)
+	}
+	return 0
+}
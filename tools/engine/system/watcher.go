@@ -0,0 +1,105 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// FSWatcher implements domain.FileWatcher with inotify where the platform has it,
+// and by polling modification times otherwise (or when inotify runs out of watches).
+type FSWatcher struct {
+	config domain.WatchConfig
+}
+
+func NewFSWatcher(config domain.WatchConfig) *FSWatcher {
+	return &FSWatcher{config: config}
+}
+
+func (w *FSWatcher) Watch(ctx context.Context, root string, skipDir func(name string) bool, onChange func(paths []string)) error {
+	switch w.config.Backend {
+	case "poll":
+		return w.poll(ctx, root, skipDir, onChange)
+	case "inotify":
+		return watchInotify(ctx, root, skipDir, onChange)
+	case "", "auto":
+		err := watchInotify(ctx, root, skipDir, onChange)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		slog.WarnContext(ctx, "inotify unavailable, polling for changes", "root", root, "interval", w.pollInterval(), "error", err)
+		return w.poll(ctx, root, skipDir, onChange)
+	}
+	return fmt.Errorf("unknown watch backend %q: must be auto, inotify or poll", w.config.Backend)
+}
+
+func (w *FSWatcher) pollInterval() time.Duration {
+	if w.config.PollInterval <= 0 {
+		return 2 * time.Second
+	}
+	return w.config.PollInterval
+}
+
+// poll rescans the tree every PollInterval and reports the files whose stamp changed,
+// appeared or disappeared since the previous scan.
+func (w *FSWatcher) poll(ctx context.Context, root string, skipDir func(name string) bool, onChange func(paths []string)) error {
+	prev := scanStamps(ctx, root, skipDir)
+	ticker := time.NewTicker(w.pollInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		cur := scanStamps(ctx, root, skipDir)
+		var changed []string
+		for path, stamp := range cur {
+			if old, ok := prev[path]; !ok || old != stamp {
+				changed = append(changed, path)
+			}
+		}
+		for path := range prev {
+			if _, ok := cur[path]; !ok {
+				changed = append(changed, path)
+			}
+		}
+		prev = cur
+
+		if len(changed) > 0 {
+			sort.Strings(changed)
+			onChange(changed)
+		}
+	}
+}
+
+func scanStamps(ctx context.Context, root string, skipDir func(name string) bool) map[string]FileStamp {
+	stamps := make(map[string]FileStamp)
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // Vanished or unreadable: reported as removed, if it was there before
+		}
+		if ctx.Err() != nil {
+			return filepath.SkipAll
+		}
+		if d.IsDir() {
+			if path != root && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			stamps[path] = stampOf(info)
+		}
+		return nil
+	})
+	return stamps
+}
@@ -0,0 +1,122 @@
+//go:build linux
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_CREATE | syscall.IN_CLOSE_WRITE | syscall.IN_DELETE |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_ONLYDIR
+
+// inotifyTree maps watch descriptors back to directories. inotify is not recursive,
+// so every directory of the tree gets its own watch, and new ones are added as they appear.
+type inotifyTree struct {
+	fd      int
+	dirs    map[int32]string
+	skipDir func(name string) bool
+}
+
+func watchInotify(ctx context.Context, root string, skipDir func(name string) bool, onChange func(paths []string)) error {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return fmt.Errorf("inotify_init1: %w", err)
+	}
+	// Non-blocking, so reads park on the runtime poller and Close wakes them up
+	events := os.NewFile(uintptr(fd), "inotify")
+
+	tree := &inotifyTree{fd: fd, dirs: make(map[int32]string), skipDir: skipDir}
+	if err := tree.add(root, nil); err != nil {
+		events.Close()
+		return err // Typically ENOSPC: fs.inotify.max_user_watches is too low for this tree
+	}
+
+	go func() {
+		<-ctx.Done()
+		events.Close()
+	}()
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := events.Read(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("reading inotify events: %w", err)
+		}
+
+		var changed []string
+		for off := 0; off+syscall.SizeofInotifyEvent <= n; {
+			ev := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			start := off + syscall.SizeofInotifyEvent
+			off = start + int(ev.Len)
+			name := strings.TrimRight(string(buf[start:off]), "\x00")
+
+			if ev.Mask&syscall.IN_Q_OVERFLOW != 0 {
+				// Events were lost: report every directory so nothing stays stale
+				for _, dir := range tree.dirs {
+					changed = append(changed, dir)
+				}
+				continue
+			}
+			dir, ok := tree.dirs[ev.Wd]
+			if !ok {
+				continue
+			}
+			if ev.Mask&syscall.IN_IGNORED != 0 {
+				delete(tree.dirs, ev.Wd) // The directory is gone
+				continue
+			}
+
+			path := filepath.Join(dir, name)
+			if ev.Mask&syscall.IN_ISDIR != 0 {
+				if skipDir(name) {
+					continue
+				}
+				if ev.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+					// Files may have landed before the watch did, so they are reported too
+					if err := tree.add(path, &changed); err != nil {
+						slog.WarnContext(ctx, "failed to watch new directory", "path", path, "error", err)
+					}
+				}
+			}
+			changed = append(changed, path)
+		}
+
+		if len(changed) > 0 {
+			onChange(changed)
+		}
+	}
+}
+
+// add watches dir and its subdirectories; files found are appended to found, if given.
+func (t *inotifyTree) add(dir string, found *[]string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // Vanished or unreadable: nothing to watch
+		}
+		if !d.IsDir() {
+			if found != nil {
+				*found = append(*found, path)
+			}
+			return nil
+		}
+		if path != dir && t.skipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		wd, err := syscall.InotifyAddWatch(t.fd, path, inotifyMask)
+		if err != nil {
+			return fmt.Errorf("inotify_add_watch %s: %w", path, err)
+		}
+		t.dirs[int32(wd)] = path
+		return nil
+	})
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package system
+
+import (
+	"context"
+	"errors"
+)
+
+func watchInotify(ctx context.Context, root string, skipDir func(name string) bool, onChange func(paths []string)) error {
+	return errors.New("inotify is only available on linux")
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Josepavese/asdp/engine/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// AgentAssetsUseCase reads the workflows and skills installed in the global ASDP
+// assets dir, so adapters can serve them without copying them into .agent/.
+type AgentAssetsUseCase struct {
+	fs     domain.FileSystem
+	config domain.Config
+}
+
+func NewAgentAssetsUseCase(fs domain.FileSystem, config domain.Config) *AgentAssetsUseCase {
+	return &AgentAssetsUseCase{fs: fs, config: config}
+}
+
+const (
+	AssetKindWorkflow = "workflow"
+	AssetKindSkill    = "skill"
+)
+
+type AgentAsset struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind"` // workflow, skill
+	Description string `json:"description"`
+	Body        string `json:"body"` // Markdown without frontmatter
+}
+
+// List returns every workflow (workflows/<name>.md) and skill (skills/<dir>/SKILL.md), sorted by name.
+func (uc *AgentAssetsUseCase) List(ctx context.Context) ([]AgentAsset, error) {
+	assetsDir, err := globalAssetsDir(uc.config)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.fs.Stat(assetsDir); err != nil {
+		return nil, fmt.Errorf("global ASDP assets not found at %s. Please run the installer first", assetsDir)
+	}
+
+	var assets []AgentAsset
+
+	workflowsDir := filepath.Join(assetsDir, "workflows")
+	if entries, err := uc.fs.ReadDir(workflowsDir); err == nil {
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") {
+				continue
+			}
+			name := strings.TrimSuffix(e.Name(), ".md")
+			if asset, err := uc.readAsset(filepath.Join(workflowsDir, e.Name()), name, AssetKindWorkflow); err == nil {
+				assets = append(assets, *asset)
+			}
+		}
+	}
+
+	skillsDir := filepath.Join(assetsDir, "skills")
+	if entries, err := uc.fs.ReadDir(skillsDir); err == nil {
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			if asset, err := uc.readAsset(filepath.Join(skillsDir, e.Name(), "SKILL.md"), e.Name(), AssetKindSkill); err == nil {
+				assets = append(assets, *asset)
+			}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(assets, func(i, j int) bool { return assets[i].Name < assets[j].Name })
+	return assets, nil
+}
+
+// Get returns the workflow or skill called name.
+func (uc *AgentAssetsUseCase) Get(ctx context.Context, name string) (*AgentAsset, error) {
+	assets, err := uc.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range assets {
+		if assets[i].Name == name {
+			return &assets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("workflow or skill %s not found", name)
+}
+
+// readAsset parses an asset file. The frontmatter `name` (skills) wins over fallbackName.
+func (uc *AgentAssetsUseCase) readAsset(path, fallbackName, kind string) (*AgentAsset, error) {
+	data, err := uc.fs.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	asset := &AgentAsset{Name: fallbackName, Kind: kind, Body: string(data)}
+
+	parts := strings.SplitN(string(data), "---", 3)
+	if len(parts) == 3 && strings.TrimSpace(parts[0]) == "" {
+		var meta struct {
+			Name        string `yaml:"name"`
+			Description string `yaml:"description"`
+		}
+		if err := yaml.Unmarshal([]byte(parts[1]), &meta); err == nil {
+			if meta.Name != "" {
+				asset.Name = meta.Name
+			}
+			asset.Description = meta.Description
+			asset.Body = strings.TrimLeft(parts[2], "\n")
+		}
+	}
+	return asset, nil
+}
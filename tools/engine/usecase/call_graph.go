@@ -0,0 +1,354 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	gotypes "go/types"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// Directions of a call graph query.
+const (
+	CallDirectionCallers = "callers"
+	CallDirectionCallees = "callees"
+	CallDirectionBoth    = "both"
+)
+
+const (
+	defaultCallDepth = 1
+	maxCallDepth     = 10
+)
+
+// CallGraphUseCase walks the static call graph a sync records in a module's codemodel
+// (parsing.go.call_graph) from one symbol, towards its callers, its callees or both.
+type CallGraphUseCase struct {
+	fs domain.FileSystem
+}
+
+func NewCallGraphUseCase(fs domain.FileSystem) *CallGraphUseCase {
+	return &CallGraphUseCase{fs: fs}
+}
+
+// CallGraphOptions choose how far the walk goes.
+type CallGraphOptions struct {
+	Direction string // CallDirectionCallers, CallDirectionCallees or CallDirectionBoth (default)
+	Depth     int    // Levels of calls to follow (default 1, at most 10)
+	Kind      string // Optional: only consider symbols of this kind for the selector
+}
+
+type CallGraphResponse struct {
+	Symbol     *SymbolRef      `json:"symbol,omitempty"`
+	Callers    []CallGraphNode `json:"callers,omitempty"`
+	Callees    []CallGraphNode `json:"callees,omitempty"`
+	Candidates []domain.Symbol `json:"candidates,omitempty"` // Set instead of Symbol when the selector is ambiguous
+	Note       string          `json:"note,omitempty"`
+}
+
+// CallGraphNode is a symbol reached from the queried one.
+type CallGraphNode struct {
+	SymbolRef
+	Depth    int    `json:"depth"`              // 1 for direct callers or callees
+	Via      string `json:"via,omitempty"`      // The node it was reached from, at Depth-1
+	Dispatch bool   `json:"dispatch,omitempty"` // Reached through an interface method, as one of its implementations
+}
+
+// Execute looks up selector (see GetFunctionInfoUseCase.ExecuteWithOptions) in the
+// codemodel of the module at modulePath and walks the graph from it.
+func (uc *CallGraphUseCase) Execute(ctx context.Context, modulePath, selector string, opts CallGraphOptions) (*CallGraphResponse, error) {
+	absPath, err := validateAndExpandPath(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	modulePath = absPath
+
+	switch opts.Direction {
+	case "":
+		opts.Direction = CallDirectionBoth
+	case CallDirectionCallers, CallDirectionCallees, CallDirectionBoth:
+	default:
+		return nil, fmt.Errorf("invalid direction %q: must be callers, callees or both", opts.Direction)
+	}
+	if opts.Depth <= 0 {
+		opts.Depth = defaultCallDepth
+	}
+	if opts.Depth > maxCallDepth {
+		opts.Depth = maxCallDepth
+	}
+
+	data, err := uc.fs.ReadFile(filepath.Join(modulePath, "codemodel.md"))
+	if err != nil {
+		return nil, fmt.Errorf("codemodel.md not found in %s. Run asdp_sync_codemodel first", modulePath)
+	}
+	model, err := parseCodeModel(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse codemodel.md: %w", err)
+	}
+	symbols := model.MetaData.Symbols
+
+	matches := selectSymbols(symbols, selector, opts.Kind)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("symbol %s not found in module %s", selector, modulePath)
+	}
+	if len(matches) > 1 {
+		return &CallGraphResponse{Candidates: matches}, nil
+	}
+
+	g := newCallGraph(symbols)
+	start := g.indexOf(matches[0])
+	resp := &CallGraphResponse{Symbol: refOf(matches[0])}
+	if !g.recorded {
+		resp.Note = "The codemodel records no calls: sync the module with parsing.go.call_graph enabled (Go sources only)"
+		return resp, nil
+	}
+	if opts.Direction != CallDirectionCallees {
+		resp.Callers = g.walk(start, g.callers, opts.Depth)
+	}
+	if opts.Direction != CallDirectionCallers {
+		resp.Callees = g.walk(start, g.callees, opts.Depth)
+	}
+	return resp, nil
+}
+
+// callEdge leads to a symbol, by index.
+type callEdge struct {
+	to       int
+	dispatch bool
+}
+
+// callGraph is the call graph of one codemodel, with calls through interface methods
+// also leading to the methods of the module's types that implement the interface.
+// A module can span several Go packages, so symbols are told apart by package directory.
+type callGraph struct {
+	symbols  []domain.Symbol
+	callees  [][]callEdge
+	callers  [][]callEdge
+	recorded bool // Some symbol records calls
+}
+
+func newCallGraph(symbols []domain.Symbol) *callGraph {
+	g := &callGraph{
+		symbols: symbols,
+		callees: make([][]callEdge, len(symbols)),
+		callers: make([][]callEdge, len(symbols)),
+	}
+
+	byName := make(map[string][]int)             // "dir:Name" or "dir:Parent.Name" of callables
+	types := make(map[string]int)                // "dir:Name" of types and interfaces
+	methods := make(map[string]map[string][]int) // "dir:Parent" -> method name -> symbols
+	packages := make(map[string]string)          // dir -> package name, when type-checked
+	for i, sym := range symbols {
+		dir := packageDir(sym)
+		switch {
+		case sym.Kind == "package":
+			packages[dir] = sym.Name
+		case callableKinds[sym.Kind]:
+			byName[dir+":"+callName(sym)] = append(byName[dir+":"+callName(sym)], i)
+			if sym.Parent != "" {
+				owner := dir + ":" + sym.Parent
+				if methods[owner] == nil {
+					methods[owner] = make(map[string][]int)
+				}
+				methods[owner][sym.Name] = append(methods[owner][sym.Name], i)
+			}
+		case sym.Parent == "":
+			types[dir+":"+sym.Name] = i
+		}
+	}
+	typeChecked := len(packages) > 0
+	isInterface := func(owner string) bool {
+		i, ok := types[owner]
+		return ok && symbols[i].Kind == "interface"
+	}
+
+	// implements reports whether the type owner implements the interface iface: by the
+	// type-checked facts when the codemodel has them, or else by a method set covering
+	// the interface's, names and parameter and result types as written
+	implements := func(owner, iface string) bool {
+		if typeChecked {
+			t, ok := types[owner]
+			if !ok {
+				return false
+			}
+			ifaceDir, ifaceName, _ := strings.Cut(iface, ":")
+			typeDir, _, _ := strings.Cut(owner, ":")
+			want := ifaceName
+			if ifaceDir != typeDir {
+				pkg, ok := packages[ifaceDir]
+				if !ok {
+					pkg = path.Base(ifaceDir)
+				}
+				want = pkg + "." + ifaceName
+			}
+			return slices.Contains(symbols[t].Implements, want)
+		}
+		for name, required := range methods[iface] {
+			found := false
+			for _, m := range methods[owner][name] {
+				if methodShape(symbols[m].Signature) == methodShape(symbols[required[0]].Signature) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+
+	// implementations returns the methods implementing interface method i
+	implementations := func(i int) []int {
+		iface := packageDir(symbols[i]) + ":" + symbols[i].Parent
+		var out []int
+		for owner, set := range methods {
+			if !isInterface(owner) && len(set[symbols[i].Name]) > 0 && implements(owner, iface) {
+				out = append(out, set[symbols[i].Name]...)
+			}
+		}
+		sort.Ints(out)
+		return out
+	}
+
+	for i, sym := range symbols {
+		if len(sym.Calls) > 0 {
+			g.recorded = true
+		}
+		for _, call := range sym.Calls {
+			if !strings.Contains(call, ":") {
+				call = packageDir(sym) + ":" + call
+			}
+			for _, j := range byName[call] {
+				g.link(i, j, false)
+				if isInterface(packageDir(symbols[j]) + ":" + symbols[j].Parent) {
+					for _, k := range implementations(j) {
+						g.link(i, k, true)
+					}
+				}
+			}
+		}
+	}
+	return g
+}
+
+// packageDir returns the directory of the package a symbol belongs to, slash-separated
+// and relative to the module, as call targets in other packages are qualified by.
+func packageDir(sym domain.Symbol) string {
+	return path.Dir(filepath.ToSlash(sym.FilePath))
+}
+
+// methodShape reduces the signature of a method, declared or in an interface, to its name
+// and the types of its parameters and results: "Get(string) (string, error)".
+func methodShape(signature string) string {
+	sig := strings.TrimPrefix(signature, "func ")
+	if strings.HasPrefix(sig, "(") {
+		depth := 0
+		for i, c := range sig {
+			if c == '(' {
+				depth++
+			} else if c == ')' {
+				if depth--; depth == 0 {
+					sig = strings.TrimSpace(sig[i+1:])
+					break
+				}
+			}
+		}
+	}
+	open := strings.IndexByte(sig, '(')
+	if open < 0 {
+		return sig
+	}
+	expr, err := parser.ParseExpr("func" + sig[open:])
+	if err != nil {
+		return sig
+	}
+	fn, ok := expr.(*ast.FuncType)
+	if !ok {
+		return sig
+	}
+	fieldTypes := func(fields *ast.FieldList) string {
+		if fields == nil {
+			return ""
+		}
+		var out []string
+		for _, f := range fields.List {
+			for n := max(len(f.Names), 1); n > 0; n-- {
+				out = append(out, gotypes.ExprString(f.Type))
+			}
+		}
+		return strings.Join(out, ", ")
+	}
+	return sig[:open] + "(" + fieldTypes(fn.Params) + ") (" + fieldTypes(fn.Results) + ")"
+}
+
+func (g *callGraph) link(from, to int, dispatch bool) {
+	g.callees[from] = append(g.callees[from], callEdge{to: to, dispatch: dispatch})
+	g.callers[to] = append(g.callers[to], callEdge{to: from, dispatch: dispatch})
+}
+
+// indexOf returns the index of sym among the graph's symbols, or -1.
+func (g *callGraph) indexOf(sym domain.Symbol) int {
+	for i, s := range g.symbols {
+		if s.Name == sym.Name && s.Parent == sym.Parent && s.FilePath == sym.FilePath && s.Line == sym.Line {
+			return i
+		}
+	}
+	return -1
+}
+
+// walk follows edges breadth-first from start, down to depth, visiting each symbol once.
+func (g *callGraph) walk(start int, edges [][]callEdge, depth int) []CallGraphNode {
+	if start < 0 {
+		return nil
+	}
+	var nodes []CallGraphNode
+	seen := map[int]bool{start: true}
+	frontier := []int{start}
+	for d := 1; d <= depth && len(frontier) > 0; d++ {
+		var level []CallGraphNode
+		var next []int
+		for _, from := range frontier {
+			for _, e := range edges[from] {
+				if seen[e.to] {
+					continue
+				}
+				seen[e.to] = true
+				next = append(next, e.to)
+				node := CallGraphNode{SymbolRef: *refOf(g.symbols[e.to]), Depth: d, Dispatch: e.dispatch}
+				if d > 1 {
+					node.Via = callName(g.symbols[from])
+				}
+				level = append(level, node)
+			}
+		}
+		sort.SliceStable(level, func(i, j int) bool {
+			return level[i].callName() < level[j].callName()
+		})
+		nodes = append(nodes, level...)
+		frontier = next
+	}
+	return nodes
+}
+
+// callName is how the call graph names a symbol: "Name" or "Parent.Name".
+func callName(sym domain.Symbol) string {
+	if sym.Parent == "" {
+		return sym.Name
+	}
+	return sym.Parent + "." + sym.Name
+}
+
+func refOf(sym domain.Symbol) *SymbolRef {
+	return &SymbolRef{Name: sym.Name, Parent: sym.Parent, Kind: sym.Kind, File: filepath.ToSlash(sym.FilePath), Line: sym.Line}
+}
+
+func (n CallGraphNode) callName() string {
+	return callName(domain.Symbol{Name: n.Name, Parent: n.Parent})
+}
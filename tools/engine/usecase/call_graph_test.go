@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// callGraphModule spans two packages, "." and "sub", with the same names in both.
+func callGraphModule() []domain.Symbol {
+	return []domain.Symbol{
+		{Name: "New", Kind: "function", FilePath: "a.go", Line: 1, Signature: "func New() *Config", Calls: []string{"Config.Load", "sub:New"}},
+		{Name: "Config", Kind: "struct", FilePath: "a.go", Line: 2},
+		{Name: "Load", Kind: "method", Parent: "Config", FilePath: "a.go", Line: 3, Signature: "func (c *Config) Load() error"},
+		{Name: "Run", Kind: "function", FilePath: "a.go", Line: 4, Signature: "func Run(s Store)", Calls: []string{"Store.Get"}},
+		{Name: "Store", Kind: "interface", FilePath: "a.go", Line: 5},
+		{Name: "Get", Kind: "method", Parent: "Store", FilePath: "a.go", Line: 6, Signature: "Get(key string) (string, error)"},
+		{Name: "mem", Kind: "struct", FilePath: "a.go", Line: 7},
+		{Name: "Get", Kind: "method", Parent: "mem", FilePath: "a.go", Line: 8, Signature: "func (m *mem) Get(k string) (string, error)"},
+		{Name: "decoy", Kind: "struct", FilePath: "a.go", Line: 9},
+		{Name: "Get", Kind: "method", Parent: "decoy", FilePath: "a.go", Line: 10, Signature: "func (d decoy) Get(id int) (string, error)"},
+
+		{Name: "New", Kind: "function", FilePath: "sub/b.go", Line: 1, Signature: "func New() *Config", Calls: []string{"Config.Load"}},
+		{Name: "Config", Kind: "struct", FilePath: "sub/b.go", Line: 2},
+		{Name: "Load", Kind: "method", Parent: "Config", FilePath: "sub/b.go", Line: 3, Signature: "func (c *Config) Load() error"},
+	}
+}
+
+func calleesOf(g *callGraph, i int) []string {
+	var out []string
+	for _, n := range g.walk(i, g.callees, 1) {
+		out = append(out, n.File+":"+n.callName())
+	}
+	return out
+}
+
+func callersOf(g *callGraph, i int) []string {
+	var out []string
+	for _, n := range g.walk(i, g.callers, 1) {
+		out = append(out, n.File+":"+n.callName())
+	}
+	return out
+}
+
+func TestCallGraphKeepsPackagesApart(t *testing.T) {
+	symbols := callGraphModule()
+	g := newCallGraph(symbols)
+
+	if got, want := calleesOf(g, 0), []string{"a.go:Config.Load", "sub/b.go:New"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("callees of New = %v, want %v", got, want)
+	}
+	if got, want := callersOf(g, 12), []string{"sub/b.go:New"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("callers of sub Config.Load = %v, want %v", got, want)
+	}
+	if got, want := callersOf(g, 2), []string{"a.go:New"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("callers of Config.Load = %v, want %v", got, want)
+	}
+}
+
+func TestCallGraphDispatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		symbols func() []domain.Symbol
+		want    []string
+	}{
+		{
+			name:    "method signatures",
+			symbols: callGraphModule,
+			want:    []string{"a.go:Store.Get", "a.go:mem.Get"},
+		},
+		{
+			name: "type-checked implements",
+			symbols: func() []domain.Symbol {
+				symbols := append(callGraphModule(), domain.Symbol{Name: "m", Kind: "package", FilePath: "a.go"})
+				symbols[8].Implements = []string{"Store"} // decoy, by the type checker's word
+				return symbols
+			},
+			want: []string{"a.go:Store.Get", "a.go:decoy.Get"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newCallGraph(tt.symbols())
+			if got := calleesOf(g, 3); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("callees of Run = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMethodShape(t *testing.T) {
+	tests := []struct{ sig, want string }{
+		{"func (m *mem) Get(k string) (string, error)", "Get(string) (string, error)"},
+		{"Get(key string) (string, error)", "Get(string) (string, error)"},
+		{"func (s *Stack[T]) Push(a, b T)", "Push(T, T) ()"},
+		{"Close() error", "Close() (error)"},
+	}
+	for _, tt := range tests {
+		if got := methodShape(tt.sig); got != tt.want {
+			t.Errorf("methodShape(%q) = %q, want %q", tt.sig, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"encoding/json"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// Items a budgeted context is assembled from, most important first.
+const (
+	packSpec              = "spec"               // Title, type, capabilities, exports, dependencies
+	packRequirements      = "requirements"       // Of the spec
+	packExportedSymbols   = "exported_symbols"   // Name, kind and location of exported symbols
+	packSignatures        = "signatures"         // Of the symbols already included
+	packDocstrings        = "docstrings"         // Of the symbols already included
+	packSpecBody          = "spec_body"          // Markdown body of codespec.md
+	packUnexportedSymbols = "unexported_symbols" // With their signatures
+	packChildren          = "children"           // Child modules, each then packed the same way
+)
+
+// estimateTokens approximates the tokens v costs in the JSON response: about four bytes per token,
+// which is close enough for English text and JSON punctuation to size a budget.
+func estimateTokens(v interface{}) int {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return (len(data) + 3) / 4
+}
+
+// contextPacker fills a token budget greedily: each item is taken if it still fits,
+// and skipped (and reported) otherwise, so smaller, lower-ranked items can use what is left.
+type contextPacker struct {
+	budget  int
+	used    int
+	omitted []domain.OmittedItems
+}
+
+func newContextPacker(maxTokens int) *contextPacker {
+	return &contextPacker{budget: maxTokens}
+}
+
+func (p *contextPacker) fits(cost int) bool {
+	if p.used+cost > p.budget {
+		return false
+	}
+	p.used += cost
+	return true
+}
+
+func (p *contextPacker) omit(path, item string, count, tokens int) {
+	if tokens == 0 {
+		return
+	}
+	p.omitted = append(p.omitted, domain.OmittedItems{Path: path, Item: item, Count: count, Tokens: tokens})
+}
+
+// pack returns a copy of full (a context loaded with docstrings and spec bodies) that fits the budget.
+// The budget report is part of the response too, so content is packed into what is left after
+// reserving room for it; if the omissions outgrow that room, packing starts over with more room.
+func (p *contextPacker) pack(full *domain.ContextResponse) *domain.ContextResponse {
+	reserve := p.reportTokens(nil)
+	for {
+		attempt := newContextPacker(p.budget - reserve)
+		out := attempt.skeleton(full)
+		attempt.used = estimateTokens(out) // The root skeleton is always returned, even over budget
+		attempt.packModule(full, out)
+
+		// reserve only grows, and no report is larger than the one omitting everything: this ends
+		report := p.reportTokens(attempt.omitted)
+		if report <= reserve {
+			out.Budget = &domain.ContextBudget{MaxTokens: p.budget, UsedTokens: attempt.used + report, Omitted: attempt.omitted}
+			return out
+		}
+		reserve = report
+	}
+}
+
+// reportTokens estimates the cost of the budget report listing omitted, with its key in the response.
+func (p *contextPacker) reportTokens(omitted []domain.OmittedItems) int {
+	report := &domain.ContextBudget{MaxTokens: p.budget, UsedTokens: p.budget, Omitted: omitted}
+	return estimateTokens(map[string]*domain.ContextBudget{"budget": report})
+}
+
+// skeleton is the part of a module that is never dropped: where it is, what it is for, and whether it is fresh.
+func (p *contextPacker) skeleton(full *domain.ContextResponse) *domain.ContextResponse {
+	out := &domain.ContextResponse{
+		Path:       full.Path,
+		Summary:    full.Summary,
+		Freshness:  full.Freshness,
+		Validation: full.Validation,
+	}
+	out.Model.MetaData.ASDPVersion = full.Model.MetaData.ASDPVersion
+	out.Model.MetaData.Integrity = full.Model.MetaData.Integrity
+	return out
+}
+
+func (p *contextPacker) packModule(full, out *domain.ContextResponse) {
+	path := full.Path
+
+	// Spec frontmatter, then its requirements
+	meta := full.Spec.MetaData
+	requirements := meta.Requirements
+	meta.Requirements = nil
+	if meta.Title != "" {
+		if cost := estimateTokens(meta); p.fits(cost) {
+			out.Spec.MetaData = meta
+		} else {
+			p.omit(path, packSpec, 0, cost)
+		}
+	}
+	if len(requirements) > 0 {
+		if cost := estimateTokens(requirements); p.fits(cost) {
+			out.Spec.MetaData.Requirements = requirements
+		} else {
+			p.omit(path, packRequirements, len(requirements), cost)
+		}
+	}
+
+	// Exported symbols, bare, then their signatures and docstrings
+	var exported, unexported []domain.Symbol
+	for _, sym := range full.Model.MetaData.Symbols {
+		if sym.Exported {
+			exported = append(exported, sym)
+		} else {
+			unexported = append(unexported, sym)
+		}
+	}
+
+	var included []int // Indexes into out.Model.MetaData.Symbols, aligned with source
+	var source []domain.Symbol
+	var missed, missedTokens int
+	for _, sym := range exported {
+		bare := sym
+		bare.Signature, bare.Docstring = "", ""
+		if cost := estimateTokens(bare); p.fits(cost) {
+			out.Model.MetaData.Symbols = append(out.Model.MetaData.Symbols, bare)
+			included = append(included, len(out.Model.MetaData.Symbols)-1)
+			source = append(source, sym)
+		} else {
+			missed, missedTokens = missed+1, missedTokens+cost
+		}
+	}
+	p.omit(path, packExportedSymbols, missed, missedTokens)
+
+	p.packSymbolText(path, packSignatures, out, included, source, func(s *domain.Symbol) *string { return &s.Signature })
+	p.packSymbolText(path, packDocstrings, out, included, source, func(s *domain.Symbol) *string { return &s.Docstring })
+
+	if full.Spec.Body != "" {
+		if cost := estimateTokens(full.Spec.Body); p.fits(cost) {
+			out.Spec.Body = full.Spec.Body
+		} else {
+			p.omit(path, packSpecBody, 0, cost)
+		}
+	}
+
+	missed, missedTokens = 0, 0
+	for _, sym := range unexported {
+		sym.Docstring = ""
+		if cost := estimateTokens(sym); p.fits(cost) {
+			out.Model.MetaData.Symbols = append(out.Model.MetaData.Symbols, sym)
+		} else {
+			missed, missedTokens = missed+1, missedTokens+cost
+		}
+	}
+	p.omit(path, packUnexportedSymbols, missed, missedTokens)
+
+	// Children: every skeleton first, so the subtree's shape survives a tight budget
+	var packed []int
+	missed, missedTokens = 0, 0
+	for i := range full.Children {
+		child := p.skeleton(&full.Children[i])
+		if cost := estimateTokens(child); p.fits(cost) {
+			out.Children = append(out.Children, *child)
+			packed = append(packed, i)
+		} else {
+			missed, missedTokens = missed+1, missedTokens+cost
+		}
+	}
+	p.omit(path, packChildren, missed, missedTokens)
+	for j, i := range packed {
+		p.packModule(&full.Children[i], &out.Children[j])
+	}
+}
+
+// packSymbolText adds one text field (signature or docstring) to the symbols already included.
+func (p *contextPacker) packSymbolText(path, item string, out *domain.ContextResponse, included []int, source []domain.Symbol, field func(*domain.Symbol) *string) {
+	var missed, missedTokens int
+	for k, idx := range included {
+		text := *field(&source[k])
+		if text == "" {
+			continue
+		}
+		if cost := estimateTokens(text); p.fits(cost) {
+			*field(&out.Model.MetaData.Symbols[idx]) = text
+		} else {
+			missed, missedTokens = missed+1, missedTokens+cost
+		}
+	}
+	p.omit(path, item, missed, missedTokens)
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+func packerContext() *domain.ContextResponse {
+	full := &domain.ContextResponse{Path: "store", Summary: "Key-value store"}
+	full.Spec.MetaData = domain.CodeSpecMeta{
+		Title:        "Store",
+		Type:         "library",
+		Requirements: []domain.Requirement{{ID: "R1", Desc: "Reads are consistent", Priority: "high"}},
+	}
+	full.Spec.Body = strings.Repeat("Design notes. ", 300)
+	full.Model.MetaData.Symbols = []domain.Symbol{
+		{Name: "Open", Kind: "function", Exported: true, Line: 10, Signature: "func Open(path string) (*DB, error)", Docstring: "Open opens the database at path."},
+		{Name: "DB", Kind: "struct", Exported: true, Line: 20, Signature: "type DB struct"},
+		{Name: "lock", Kind: "function", Line: 30, Signature: "func lock(path string) error", Docstring: "Dropped from unexported symbols."},
+	}
+	full.Children = []domain.ContextResponse{{Path: "store/wal", Summary: "Write-ahead log"}}
+	full.Children[0].Spec.MetaData.Title = "WAL"
+	return full
+}
+
+func TestPackUnlimited(t *testing.T) {
+	full := packerContext()
+	out := newContextPacker(100000).pack(full)
+
+	if len(out.Budget.Omitted) != 0 {
+		t.Errorf("omitted %+v with room to spare", out.Budget.Omitted)
+	}
+	if out.Spec.Body != full.Spec.Body || !reflect.DeepEqual(out.Spec.MetaData, full.Spec.MetaData) {
+		t.Errorf("spec was not kept whole: %+v", out.Spec.MetaData)
+	}
+	want := []domain.Symbol{full.Model.MetaData.Symbols[0], full.Model.MetaData.Symbols[1], full.Model.MetaData.Symbols[2]}
+	want[2].Docstring = ""
+	if !reflect.DeepEqual(out.Model.MetaData.Symbols, want) {
+		t.Errorf("symbols:\n%+v\nwant\n%+v", out.Model.MetaData.Symbols, want)
+	}
+	if len(out.Children) != 1 || out.Children[0].Spec.MetaData.Title != "WAL" {
+		t.Errorf("children = %+v", out.Children)
+	}
+}
+
+func TestPackSkipsWhatDoesNotFit(t *testing.T) {
+	full := packerContext()
+	bodyTokens := estimateTokens(full.Spec.Body)
+	all := newContextPacker(100000).pack(full).Budget.UsedTokens
+
+	// Everything but the spec body and its line in the report fits: the smaller items after it are still taken
+	want := []domain.OmittedItems{{Path: "store", Item: packSpecBody, Tokens: bodyTokens}}
+	reported := newContextPacker(all)
+	out := newContextPacker(all - bodyTokens - reported.reportTokens(nil) + reported.reportTokens(want)).pack(full)
+	if out.Spec.Body != "" {
+		t.Error("spec body packed over budget")
+	}
+	if !reflect.DeepEqual(out.Budget.Omitted, want) {
+		t.Errorf("omitted = %+v, want %+v", out.Budget.Omitted, want)
+	}
+	if len(out.Model.MetaData.Symbols) != 3 || len(out.Children) != 1 {
+		t.Errorf("lower-ranked items were dropped: %d symbols, %d children", len(out.Model.MetaData.Symbols), len(out.Children))
+	}
+	if out.Budget.UsedTokens > out.Budget.MaxTokens {
+		t.Errorf("used %d of %d tokens", out.Budget.UsedTokens, out.Budget.MaxTokens)
+	}
+}
+
+func TestPackCountsTheReport(t *testing.T) {
+	full := packerContext()
+	all := newContextPacker(100000).pack(full).Budget.UsedTokens
+
+	// Only a budget too small for the skeleton and the report alone is overrun
+	for max := 1; max <= all; max++ {
+		out := newContextPacker(max).pack(full)
+		if used := out.Budget.UsedTokens; used > max && (out.Spec.MetaData.Title != "" || len(out.Model.MetaData.Symbols) != 0 || len(out.Children) != 0) {
+			t.Fatalf("used %d of %d tokens", used, max)
+		}
+		if actual := estimateTokens(out); actual > out.Budget.UsedTokens {
+			t.Fatalf("budget %d: response of %d tokens reported as %d", max, actual, out.Budget.UsedTokens)
+		}
+	}
+}
+
+func TestPackKeepsSkeletonOverBudget(t *testing.T) {
+	full := packerContext()
+	out := newContextPacker(1).pack(full)
+
+	if out.Path != "store" || out.Summary != "Key-value store" {
+		t.Errorf("skeleton lost: %+v", out)
+	}
+	if out.Spec.MetaData.Title != "" || len(out.Model.MetaData.Symbols) != 0 || len(out.Children) != 0 {
+		t.Errorf("content packed into a 1-token budget: %+v", out)
+	}
+	var items []string
+	for _, o := range out.Budget.Omitted {
+		items = append(items, o.Item)
+		if o.Tokens == 0 {
+			t.Errorf("%s omitted with no token estimate", o.Item)
+		}
+	}
+	want := []string{packSpec, packRequirements, packExportedSymbols, packSpecBody, packUnexportedSymbols, packChildren}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("omitted items %v, want %v", items, want)
+	}
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// DependentsUseCase answers "who depends on this module" from the logical
+// dependencies declared in every codespec.md listed in the project codetree.
+type DependentsUseCase struct {
+	fs domain.FileSystem
+}
+
+func NewDependentsUseCase(fs domain.FileSystem) *DependentsUseCase {
+	return &DependentsUseCase{fs: fs}
+}
+
+type DependentsResponse struct {
+	Module     string              `json:"module"` // Relative to the project root, "." for the root
+	ID         string              `json:"id,omitempty"`
+	Direct     []Dependent         `json:"direct"`
+	Transitive []Dependent         `json:"transitive"`
+	Dangling   []DanglingReference `json:"dangling,omitempty"` // Project-wide references to unknown modules
+}
+
+// Dependent is a module that depends on the queried one, directly or through Via.
+type Dependent struct {
+	Module string `json:"module"`
+	ID     string `json:"id,omitempty"`
+	Reason string `json:"reason"`        // As stated in the dependent's codespec.md
+	Via    string `json:"via,omitempty"` // For transitive dependents: the module it depends on
+	Depth  int    `json:"depth"`         // 1 for direct dependents
+}
+
+// DanglingReference is a declared dependency that matches no module of the codetree.
+type DanglingReference struct {
+	From   string `json:"from"`
+	Module string `json:"module"`
+	Reason string `json:"reason"`
+}
+
+type specModule struct {
+	relPath string
+	id      string
+	deps    []domain.Dependency
+}
+
+type dependencyEdge struct {
+	from, to int
+	reason   string
+}
+
+// dependencyIndex resolves the free-form `module` of each dependency, which may be
+// a spec id, a path relative to the project root or to the declaring module, or a
+// directory name when it is unique.
+type dependencyIndex struct {
+	modules  []specModule
+	keys     map[string]int
+	names    map[string][]int
+	edges    []dependencyEdge
+	dangling []DanglingReference
+}
+
+func (uc *DependentsUseCase) Execute(ctx context.Context, root string, module string) (*DependentsResponse, error) {
+	absPath, err := validateAndExpandPath(root)
+	if err != nil {
+		return nil, err
+	}
+	root = absPath
+	if module == "" {
+		return nil, fmt.Errorf("module cannot be empty")
+	}
+
+	index, err := uc.buildIndex(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	// The target may be given as an absolute path too
+	ref := module
+	if filepath.IsAbs(ref) {
+		if rel, err := filepath.Rel(root, ref); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			ref = filepath.ToSlash(rel)
+		}
+	}
+	target, ok := index.resolve(ref, "")
+	if !ok {
+		return nil, fmt.Errorf("module %q is not listed in %s/codetree.md", module, root)
+	}
+
+	resp := &DependentsResponse{
+		Module:     index.modules[target].relPath,
+		ID:         index.modules[target].id,
+		Direct:     []Dependent{},
+		Transitive: []Dependent{},
+		Dangling:   index.dangling,
+	}
+
+	// Breadth-first over reversed edges: each dependent is reported at its shortest distance
+	seen := map[int]bool{target: true}
+	frontier := []int{target}
+	for depth := 1; len(frontier) > 0; depth++ {
+		var next []int
+		for _, to := range frontier {
+			for _, e := range index.edges {
+				if e.to != to || seen[e.from] {
+					continue
+				}
+				seen[e.from] = true
+				next = append(next, e.from)
+
+				d := Dependent{
+					Module: index.modules[e.from].relPath,
+					ID:     index.modules[e.from].id,
+					Reason: e.reason,
+					Depth:  depth,
+				}
+				if depth == 1 {
+					resp.Direct = append(resp.Direct, d)
+				} else {
+					d.Via = index.modules[to].relPath
+					resp.Transitive = append(resp.Transitive, d)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return resp, nil
+}
+
+func (uc *DependentsUseCase) buildIndex(ctx context.Context, root string) (*dependencyIndex, error) {
+	treeModules, err := listTreeModules(uc.fs, root)
+	if err != nil {
+		return nil, err
+	}
+
+	index := &dependencyIndex{keys: make(map[string]int), names: make(map[string][]int)}
+	for _, m := range treeModules {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		rel, _ := filepath.Rel(root, m.Path)
+		sm := specModule{relPath: filepath.ToSlash(rel)}
+		if m.Component.HasSpec {
+			if data, err := uc.fs.ReadFile(filepath.Join(m.Path, "codespec.md")); err == nil {
+				if spec, err := parseCodeSpec(data); err == nil && spec != nil {
+					sm.id = spec.MetaData.ID
+					sm.deps = spec.MetaData.Dependencies
+				}
+			}
+		}
+
+		i := len(index.modules)
+		index.modules = append(index.modules, sm)
+		index.keys[sm.relPath] = i
+		if sm.id != "" {
+			index.keys[sm.id] = i
+		}
+		name := filepath.Base(m.Path)
+		index.names[name] = append(index.names[name], i)
+	}
+
+	for from, m := range index.modules {
+		for _, dep := range m.deps {
+			to, ok := index.resolve(dep.Module, m.relPath)
+			if !ok {
+				index.dangling = append(index.dangling, DanglingReference{From: m.relPath, Module: dep.Module, Reason: dep.Reason})
+				continue
+			}
+			if to != from {
+				index.edges = append(index.edges, dependencyEdge{from: from, to: to, reason: dep.Reason})
+			}
+		}
+	}
+	sort.SliceStable(index.edges, func(i, j int) bool {
+		return index.modules[index.edges[i].from].relPath < index.modules[index.edges[j].from].relPath
+	})
+
+	return index, nil
+}
+
+func (idx *dependencyIndex) resolve(ref string, fromRel string) (int, bool) {
+	ref = strings.TrimSpace(ref)
+	clean := filepath.ToSlash(filepath.Clean(strings.TrimPrefix(ref, "./")))
+
+	if i, ok := idx.keys[ref]; ok {
+		return i, true
+	}
+	if i, ok := idx.keys[clean]; ok {
+		return i, true
+	}
+	if fromRel != "" {
+		if i, ok := idx.keys[filepath.ToSlash(filepath.Join(fromRel, clean))]; ok {
+			return i, true
+		}
+	}
+	if candidates := idx.names[filepath.Base(clean)]; len(candidates) == 1 && !strings.Contains(clean, "/") {
+		return candidates[0], true
+	}
+	return 0, false
+}
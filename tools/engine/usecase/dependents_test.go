@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Josepavese/asdp/engine/system"
+)
+
+func dependentsProject(t *testing.T) string {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "codetree.md"), `---
+asdp_version: 0.1.0
+components:
+  - {name: store, path: store, has_spec: true}
+  - {name: api, path: api, has_spec: true}
+  - {name: web, path: web, has_spec: true}
+  - name: cmd
+    path: cmd
+    children:
+      - {name: cli, path: cmd/cli, has_spec: true}
+---
+`)
+	writeFile(t, filepath.Join(root, "store", "codespec.md"), "---\nid: core.store\ntitle: Store\n---\n")
+	writeFile(t, filepath.Join(root, "api", "codespec.md"), `---
+title: API
+dependencies:
+  - {module: core.store, reason: persists requests}
+---
+`)
+	writeFile(t, filepath.Join(root, "web", "codespec.md"), `---
+title: Web
+dependencies:
+  - {module: ./store, reason: reads pages}
+  - {module: web, reason: itself}
+---
+`)
+	writeFile(t, filepath.Join(root, "cmd", "cli", "codespec.md"), `---
+title: CLI
+dependencies:
+  - {module: api, reason: serves it}
+  - {module: ghost, reason: was removed}
+---
+`)
+	return root
+}
+
+func TestDependents(t *testing.T) {
+	root := dependentsProject(t)
+	uc := NewDependentsUseCase(system.NewRealFileSystem())
+
+	for _, module := range []string{"store", "core.store", filepath.Join(root, "store")} {
+		resp, err := uc.Execute(context.Background(), root, module)
+		if err != nil {
+			t.Fatalf("%s: %v", module, err)
+		}
+		want := &DependentsResponse{
+			Module: "store",
+			ID:     "core.store",
+			Direct: []Dependent{
+				{Module: "api", Reason: "persists requests", Depth: 1},
+				{Module: "web", Reason: "reads pages", Depth: 1},
+			},
+			Transitive: []Dependent{{Module: "cmd/cli", Reason: "serves it", Via: "api", Depth: 2}},
+			Dangling:   []DanglingReference{{From: "cmd/cli", Module: "ghost", Reason: "was removed"}},
+		}
+		if !reflect.DeepEqual(resp, want) {
+			t.Errorf("%s:\n%+v\nwant\n%+v", module, resp, want)
+		}
+	}
+}
+
+func TestDependentsUnknownModule(t *testing.T) {
+	root := dependentsProject(t)
+	uc := NewDependentsUseCase(system.NewRealFileSystem())
+
+	if _, err := uc.Execute(context.Background(), root, "ghost"); err == nil {
+		t.Error("no error for a module missing from the codetree")
+	}
+	resp, err := uc.Execute(context.Background(), root, "cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Direct) != 0 || len(resp.Transitive) != 0 {
+		t.Errorf("cli has dependents: %+v", resp)
+	}
+}
+
+func TestDependentsOfTwoDotModule(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "codetree.md"), `---
+components:
+  - {name: ..data, path: ..data, has_spec: true}
+  - {name: api, path: api, has_spec: true}
+---
+`)
+	writeFile(t, filepath.Join(root, "..data", "codespec.md"), "---\ntitle: Data\n---\n")
+	writeFile(t, filepath.Join(root, "api", "codespec.md"), "---\ntitle: API\ndependencies:\n  - {module: ..data, reason: reads it}\n---\n")
+
+	resp, err := NewDependentsUseCase(system.NewRealFileSystem()).Execute(context.Background(), root, filepath.Join(root, "..data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Dependent{{Module: "api", Reason: "reads it", Depth: 1}}; resp.Module != "..data" || !reflect.DeepEqual(resp.Direct, want) {
+		t.Errorf("dependents of %s = %+v, want %+v", resp.Module, resp.Direct, want)
+	}
+}
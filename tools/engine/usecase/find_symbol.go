@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// FindSymbolUseCase searches the symbols of every codemodel.md listed in the project codetree.
+type FindSymbolUseCase struct {
+	fs domain.FileSystem
+}
+
+func NewFindSymbolUseCase(fs domain.FileSystem) *FindSymbolUseCase {
+	return &FindSymbolUseCase{fs: fs}
+}
+
+// Match modes for SymbolQuery.Match.
+const (
+	MatchExact  = "exact"  // Case-sensitive name equality
+	MatchPrefix = "prefix" // Case-insensitive
+	MatchFuzzy  = "fuzzy"  // Case-insensitive subsequence, best matches first
+)
+
+const defaultSymbolLimit = 50
+
+// SymbolQuery selects symbols by name. A query of the form Parent.Name also
+// requires the symbol's Parent (e.g. a method's receiver type) to match: exactly
+// in exact mode, ignoring case in the others.
+type SymbolQuery struct {
+	Query    string
+	Match    string // MatchExact (default), MatchPrefix or MatchFuzzy
+	Kind     string // Optional: function, method, struct, interface, ...
+	Exported *bool  // Optional
+	Limit    int    // Default 50
+}
+
+type SymbolMatch struct {
+	Module    string `json:"module"` // Relative to the project root, "." for the root
+	File      string `json:"file"`   // Relative to the project root
+	Name      string `json:"name"`
+	Parent    string `json:"parent,omitempty"`
+	Kind      string `json:"kind"`
+	Exported  bool   `json:"exported"`
+	Line      int    `json:"line"`
+	LineEnd   int    `json:"line_end"`
+	Signature string `json:"signature"`
+	score     int
+}
+
+type FindSymbolResponse struct {
+	Query     string        `json:"query"`
+	Match     string        `json:"match"`
+	Total     int           `json:"total"`     // Matches before Limit
+	Truncated bool          `json:"truncated"` // Total > len(Matches)
+	Matches   []SymbolMatch `json:"matches"`
+}
+
+func (uc *FindSymbolUseCase) Execute(ctx context.Context, root string, q SymbolQuery) (*FindSymbolResponse, error) {
+	absPath, err := validateAndExpandPath(root)
+	if err != nil {
+		return nil, err
+	}
+	root = absPath
+
+	if q.Query == "" {
+		return nil, fmt.Errorf("query cannot be empty")
+	}
+	if q.Match == "" {
+		q.Match = MatchExact
+	}
+	if q.Match != MatchExact && q.Match != MatchPrefix && q.Match != MatchFuzzy {
+		return nil, fmt.Errorf("unknown match mode '%s': must be exact, prefix or fuzzy", q.Match)
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultSymbolLimit
+	}
+
+	parent, name := "", q.Query
+	if i := strings.LastIndex(q.Query, "."); i > 0 && i < len(q.Query)-1 {
+		parent, name = q.Query[:i], q.Query[i+1:]
+	}
+
+	modules, err := listTreeModules(uc.fs, root)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &FindSymbolResponse{Query: q.Query, Match: q.Match, Matches: []SymbolMatch{}}
+	for _, m := range modules {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !m.Component.HasModel {
+			continue
+		}
+		data, err := uc.fs.ReadFile(filepath.Join(m.Path, "codemodel.md"))
+		if err != nil {
+			continue
+		}
+		model, err := parseCodeModel(data)
+		if err != nil {
+			continue
+		}
+
+		rel, _ := filepath.Rel(root, m.Path)
+		for _, sym := range model.MetaData.Symbols {
+			if q.Kind != "" && sym.Kind != q.Kind {
+				continue
+			}
+			if q.Exported != nil && sym.Exported != *q.Exported {
+				continue
+			}
+			if parent != "" && !matchSymbolParent(q.Match, parent, strings.TrimPrefix(sym.Parent, "*")) {
+				continue
+			}
+			score, ok := matchSymbolName(q.Match, name, sym.Name)
+			if !ok {
+				continue
+			}
+			resp.Matches = append(resp.Matches, SymbolMatch{
+				Module:    filepath.ToSlash(rel),
+				File:      filepath.ToSlash(filepath.Join(rel, sym.FilePath)),
+				Name:      sym.Name,
+				Parent:    sym.Parent,
+				Kind:      sym.Kind,
+				Exported:  sym.Exported,
+				Line:      sym.Line,
+				LineEnd:   sym.LineEnd,
+				Signature: sym.Signature,
+				score:     score,
+			})
+		}
+	}
+
+	sort.SliceStable(resp.Matches, func(i, j int) bool {
+		a, b := resp.Matches[i], resp.Matches[j]
+		if a.score != b.score {
+			return a.score < b.score
+		}
+		if a.Module != b.Module {
+			return a.Module < b.Module
+		}
+		return a.Line < b.Line
+	})
+
+	resp.Total = len(resp.Matches)
+	if len(resp.Matches) > q.Limit {
+		resp.Matches = resp.Matches[:q.Limit]
+		resp.Truncated = true
+	}
+	return resp, nil
+}
+
+// matchSymbolParent reports whether a symbol's parent is the one a qualified query
+// names: equal in exact mode, equal ignoring case otherwise.
+func matchSymbolParent(mode, query, parent string) bool {
+	if mode == MatchExact {
+		return parent == query
+	}
+	return strings.EqualFold(parent, query)
+}
+
+// matchSymbolName reports whether name matches query in the given mode, with a
+// score where lower is better (only fuzzy matching ranks).
+func matchSymbolName(mode, query, name string) (int, bool) {
+	switch mode {
+	case MatchExact:
+		return 0, name == query
+	case MatchPrefix:
+		return 0, strings.HasPrefix(strings.ToLower(name), strings.ToLower(query))
+	}
+
+	// Fuzzy: every query rune must appear in order; gaps and leftovers cost points
+	q, n := []rune(strings.ToLower(query)), []rune(strings.ToLower(name))
+	score, qi, last := 0, 0, -1
+	for ni := 0; ni < len(n) && qi < len(q); ni++ {
+		if n[ni] != q[qi] {
+			continue
+		}
+		if last >= 0 {
+			score += ni - last - 1
+		} else {
+			score += ni // Matches late in the name rank lower
+		}
+		last = ni
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return score + len(n) - len(q), true
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Josepavese/asdp/engine/system"
+)
+
+func findSymbolProject(t *testing.T) string {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "codetree.md"), `---
+asdp_version: 0.1.0
+components:
+  - {name: store, path: store, has_model: true}
+  - name: cmd
+    path: cmd
+    children:
+      - {name: api, path: cmd/api, has_model: true}
+---
+`)
+	writeFile(t, filepath.Join(root, "store", "codemodel.md"), `---
+symbols:
+  - {name: Config, kind: struct, exported: true, line: 3, file_path: config.go}
+  - {name: Load, kind: method, parent: "*Config", exported: true, line: 10, file_path: config.go}
+  - {name: load, kind: function, line: 20, file_path: config.go}
+  - {name: Loader, kind: interface, exported: true, line: 30, file_path: config.go}
+  - {name: config, kind: variable, line: 40, file_path: config.go}
+---
+`)
+	writeFile(t, filepath.Join(root, "cmd", "api", "codemodel.md"), `---
+symbols:
+  - {name: Config, kind: struct, exported: true, line: 5, file_path: api.go}
+  - {name: Handle, kind: function, exported: true, line: 8, file_path: api.go}
+  - {name: LoadAll, kind: function, exported: true, line: 12, file_path: api.go}
+---
+`)
+	return root
+}
+
+func TestFindSymbol(t *testing.T) {
+	root := findSymbolProject(t)
+	uc := NewFindSymbolUseCase(system.NewRealFileSystem())
+	no := false
+
+	tests := []struct {
+		name  string
+		query SymbolQuery
+		want  []string
+	}{
+		{"exact", SymbolQuery{Query: "Config"}, []string{"cmd/api/api.go:Config", "store/config.go:Config"}},
+		{"exact is case-sensitive", SymbolQuery{Query: "config"}, []string{"store/config.go:config"}},
+		{"exact qualified", SymbolQuery{Query: "Config.Load"}, []string{"store/config.go:*Config.Load"}},
+		{"exact qualified parent is case-sensitive", SymbolQuery{Query: "config.Load"}, nil},
+		{"prefix", SymbolQuery{Query: "load", Match: MatchPrefix}, []string{
+			"cmd/api/api.go:LoadAll", "store/config.go:*Config.Load", "store/config.go:load", "store/config.go:Loader",
+		}},
+		{"prefix qualified ignores case", SymbolQuery{Query: "config.lo", Match: MatchPrefix}, []string{"store/config.go:*Config.Load"}},
+		{"fuzzy ranking", SymbolQuery{Query: "ld", Match: MatchFuzzy}, []string{
+			"store/config.go:*Config.Load", "store/config.go:load", "store/config.go:Loader", "cmd/api/api.go:LoadAll",
+		}},
+		{"fuzzy qualified", SymbolQuery{Query: "CONFIG.ld", Match: MatchFuzzy}, []string{"store/config.go:*Config.Load"}},
+		{"kind", SymbolQuery{Query: "load", Match: MatchPrefix, Kind: "function"}, []string{"cmd/api/api.go:LoadAll", "store/config.go:load"}},
+		{"unexported", SymbolQuery{Query: "load", Match: MatchPrefix, Exported: &no}, []string{"store/config.go:load"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := uc.Execute(context.Background(), root, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, m := range resp.Matches {
+				name := m.Name
+				if m.Parent != "" {
+					name = m.Parent + "." + name
+				}
+				got = append(got, m.File+":"+name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matches %v, want %v", got, tt.want)
+			}
+			if resp.Total != len(tt.want) || resp.Truncated {
+				t.Errorf("total %d, truncated %v", resp.Total, resp.Truncated)
+			}
+		})
+	}
+}
+
+func TestFindSymbolLimit(t *testing.T) {
+	root := findSymbolProject(t)
+	uc := NewFindSymbolUseCase(system.NewRealFileSystem())
+
+	resp, err := uc.Execute(context.Background(), root, SymbolQuery{Query: "load", Match: MatchPrefix, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Matches) != 2 || resp.Total != 4 || !resp.Truncated {
+		t.Errorf("%d matches, total %d, truncated %v; want 2, 4, true", len(resp.Matches), resp.Total, resp.Truncated)
+	}
+	if resp.Matches[0].Name != "LoadAll" || resp.Matches[0].Module != "cmd/api" || resp.Matches[0].Line != 12 {
+		t.Errorf("first match %+v", resp.Matches[0])
+	}
+}
+
+func TestFindSymbolRejectsBadQueries(t *testing.T) {
+	root := findSymbolProject(t)
+	uc := NewFindSymbolUseCase(system.NewRealFileSystem())
+
+	for _, q := range []SymbolQuery{{}, {Query: "Config", Match: "regex"}} {
+		if _, err := uc.Execute(context.Background(), root, q); err == nil {
+			t.Errorf("no error for %+v", q)
+		}
+	}
+}
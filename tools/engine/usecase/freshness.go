@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// FreshnessService is the one definition of freshness, shared by asdp_query_context,
+// asdp_validate and watch mode. The codemodel's src_hash alone decides whether it is
+// stale; modification times only point at the files that likely caused a drift, since
+// a checkout, clone or touch changes them without changing any content.
+type FreshnessService struct {
+	fs     domain.FileSystem
+	hasher domain.ContentHasher
+	config domain.Config
+}
+
+func NewFreshnessService(fs domain.FileSystem, hasher domain.ContentHasher, config domain.Config) *FreshnessService {
+	return &FreshnessService{fs: fs, hasher: hasher, config: config}
+}
+
+// CheckModule reads the codespec.md and codemodel.md of the module at modulePath and checks them.
+func (s *FreshnessService) CheckModule(ctx context.Context, modulePath string) (domain.Freshness, error) {
+	var spec *domain.CodeSpec
+	if data, err := s.fs.ReadFile(filepath.Join(modulePath, "codespec.md")); err == nil {
+		spec, _ = parseCodeSpec(data)
+	}
+	var model *domain.CodeModel
+	if data, err := s.fs.ReadFile(filepath.Join(modulePath, "codemodel.md")); err == nil {
+		model, _ = parseCodeModel(data)
+	}
+	return s.Check(ctx, modulePath, spec, model)
+}
+
+// Check compares a module's codemodel (and codespec, which may be nil) with its sources.
+func (s *FreshnessService) Check(ctx context.Context, modulePath string, spec *domain.CodeSpec, model *domain.CodeModel) (domain.Freshness, error) {
+	if model == nil || model.MetaData.ASDPVersion == "" {
+		return domain.Freshness{Status: "unknown", Reason: "No codemodel.md found"}, nil
+	}
+	integrity := model.MetaData.Integrity
+
+	currentHash, manifest, err := s.hasher.ManifestDir(ctx, modulePath)
+	if err != nil {
+		if ctx.Err() != nil {
+			return domain.Freshness{}, ctx.Err()
+		}
+		return domain.Freshness{Status: "unknown", Reason: fmt.Sprintf("Hashing failed: %v", err)}, nil
+	}
+	sources, err := moduleSourceFiles(ctx, s.fs, modulePath, manifest)
+	if err != nil {
+		return domain.Freshness{}, err
+	}
+
+	f := domain.Freshness{Status: "fresh", CurrentHash: currentHash, DocHash: integrity.SrcHash}
+	touched := modifiedAfter(sources, integrity.CheckedAt)
+	switch {
+	case integrity.SrcHash == s.config.Sync.Model.FirstSyncHash:
+		f.Status, f.Reason = "stale", "New module, never synced"
+		f.Issues = append(f.Issues, domain.FreshnessIssue{
+			Code:    domain.FreshnessNeverSynced,
+			Message: "codemodel.md was scaffolded but never synced",
+		})
+	case integrity.SrcHash != currentHash && integrity.Files != nil:
+		// The manifest of the last sync names exactly what changed
+		f.Status, f.Reason = "stale", "Source code changed"
+		diff := diffManifests(integrity.Files, manifest)
+		f.Issues = append(f.Issues, domain.FreshnessIssue{
+			Code: domain.FreshnessHashDrift,
+			Message: fmt.Sprintf("%d file(s) modified, %d added, %d removed since the last sync",
+				len(diff.Modified), len(diff.Added), len(diff.Removed)),
+			Files:    diff.changed(),
+			Added:    diff.Added,
+			Removed:  diff.Removed,
+			Modified: diff.Modified,
+		})
+	case integrity.SrcHash != currentHash:
+		f.Status, f.Reason = "stale", "Source code changed"
+		msg := fmt.Sprintf("%d file(s) modified since the last sync (%s)", len(touched), integrity.CheckedAt.Format(time.RFC3339))
+		if len(touched) == 0 {
+			msg = "sources differ from the last sync, but no file was modified after it (e.g. a checkout or a removed file)"
+		}
+		f.Issues = append(f.Issues, domain.FreshnessIssue{Code: domain.FreshnessHashDrift, Message: msg, Files: touched})
+	case len(touched) > 0:
+		f.Issues = append(f.Issues, domain.FreshnessIssue{
+			Code:    domain.FreshnessMtimeDrift,
+			Message: fmt.Sprintf("%d file(s) touched since the last sync, content unchanged", len(touched)),
+			Files:   touched,
+		})
+	}
+
+	// The spec is behind when the sources, as of the last sync, changed after its declared last_modified
+	if spec != nil && !spec.MetaData.LastModified.IsZero() && integrity.LastModified.After(spec.MetaData.LastModified) {
+		f.Issues = append(f.Issues, domain.FreshnessIssue{
+			Code: domain.FreshnessSpecBehindModel,
+			Message: fmt.Sprintf("sources changed (%s) after the codespec's last_modified (%s)",
+				integrity.LastModified.Format(time.RFC3339), spec.MetaData.LastModified.Format(time.RFC3339)),
+			Files: modifiedAfter(sources, spec.MetaData.LastModified),
+		})
+	}
+	return f, nil
+}
+
+// sourceFile is a file that feeds a module's src_hash.
+type sourceFile struct {
+	rel     string // Relative to the module
+	modTime time.Time
+}
+
+// moduleSourceFiles stats the files of a manifest from ManifestDir (slash-separated,
+// relative to dir): exactly the files behind the module's current src_hash.
+func moduleSourceFiles(ctx context.Context, fs domain.FileSystem, dir string, manifest map[string]string) ([]sourceFile, error) {
+	files := make([]sourceFile, 0, len(manifest))
+	for rel := range manifest {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		info, err := fs.Stat(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			continue // Removed since it was hashed
+		}
+		files = append(files, sourceFile{rel: rel, modTime: info.ModTime()})
+	}
+	return files, nil
+}
+
+// modifiedAfter returns the files modified after t, sorted.
+func modifiedAfter(files []sourceFile, t time.Time) []string {
+	var out []string
+	for _, f := range files {
+		if f.modTime.After(t) {
+			out = append(out, f.rel)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// manifestDiff is how the files of a module changed between two file manifests.
+type manifestDiff struct {
+	Added    []string
+	Removed  []string
+	Modified []string
+}
+
+func diffManifests(previous, current map[string]string) manifestDiff {
+	var d manifestDiff
+	for file, hash := range current {
+		if old, ok := previous[file]; !ok {
+			d.Added = append(d.Added, file)
+		} else if old != hash {
+			d.Modified = append(d.Modified, file)
+		}
+	}
+	for file := range previous {
+		if _, ok := current[file]; !ok {
+			d.Removed = append(d.Removed, file)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Strings(d.Modified)
+	return d
+}
+
+// changed returns every added, removed or modified file, sorted.
+func (d manifestDiff) changed() []string {
+	files := append(append(append([]string{}, d.Added...), d.Removed...), d.Modified...)
+	sort.Strings(files)
+	return files
+}
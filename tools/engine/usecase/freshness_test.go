@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Josepavese/asdp/engine/domain"
+	"github.com/Josepavese/asdp/engine/system"
+)
+
+func newTestFreshness(config *domain.Config) *FreshnessService {
+	return NewFreshnessService(system.NewRealFileSystem(), system.NewSHA256ContentHasher(config.Hasher), *config)
+}
+
+// touch sets the modification time of path to an hour from now, after any sync of the test.
+func touch(t *testing.T, path string) {
+	t.Helper()
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func issueCodes(f domain.Freshness) []string {
+	var codes []string
+	for _, issue := range f.Issues {
+		codes = append(codes, issue.Code)
+	}
+	return codes
+}
+
+func TestFreshnessByHash(t *testing.T) {
+	config := domain.DefaultConfig()
+	config.Sync.Model.FileManifest = false
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.go"), "package m\n\nfunc A() {}\n")
+	writeFile(t, filepath.Join(dir, "b.go"), "package m\n\nfunc B() {}\n")
+	ctx := context.Background()
+	if _, err := newTestSync(config).Execute(ctx, dir); err != nil {
+		t.Fatal(err)
+	}
+	svc := newTestFreshness(config)
+
+	f, err := svc.CheckModule(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Status != "fresh" || len(f.Issues) != 0 {
+		t.Errorf("after a sync: %+v", f)
+	}
+
+	// A touch changes no content: still fresh, with an informational issue
+	touch(t, filepath.Join(dir, "a.go"))
+	if f, _ = svc.CheckModule(ctx, dir); f.Status != "fresh" || !reflect.DeepEqual(issueCodes(f), []string{domain.FreshnessMtimeDrift}) {
+		t.Errorf("after a touch: %+v", f)
+	} else if !reflect.DeepEqual(f.Issues[0].Files, []string{"a.go"}) {
+		t.Errorf("touched files = %v, want [a.go]", f.Issues[0].Files)
+	}
+
+	writeFile(t, filepath.Join(dir, "b.go"), "package m\n\nfunc B() { A() }\n")
+	touch(t, filepath.Join(dir, "b.go"))
+	if f, _ = svc.CheckModule(ctx, dir); f.Status != "stale" || !reflect.DeepEqual(issueCodes(f), []string{domain.FreshnessHashDrift}) {
+		t.Errorf("after an edit: %+v", f)
+	} else if !reflect.DeepEqual(f.Issues[0].Files, []string{"a.go", "b.go"}) {
+		t.Errorf("drifted files = %v, want [a.go b.go]", f.Issues[0].Files)
+	}
+}
+
+func TestFreshnessWithoutSync(t *testing.T) {
+	config := domain.DefaultConfig()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.go"), "package m\n")
+	svc := newTestFreshness(config)
+	ctx := context.Background()
+
+	if f, err := svc.Check(ctx, dir, nil, nil); err != nil || f.Status != "unknown" {
+		t.Errorf("without a codemodel: %+v, %v", f, err)
+	}
+
+	scaffolded := &domain.CodeModel{}
+	scaffolded.MetaData.ASDPVersion = domain.Version
+	scaffolded.MetaData.Integrity.SrcHash = config.Sync.Model.FirstSyncHash
+	f, err := svc.Check(ctx, dir, nil, scaffolded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Status != "stale" || !reflect.DeepEqual(issueCodes(f), []string{domain.FreshnessNeverSynced}) {
+		t.Errorf("scaffolded codemodel: %+v", f)
+	}
+}
+
+func TestFreshnessSpecBehindModel(t *testing.T) {
+	config := domain.DefaultConfig()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.go"), "package m\n\nfunc A() {}\n")
+	ctx := context.Background()
+	if _, err := newTestSync(config).Execute(ctx, dir); err != nil {
+		t.Fatal(err)
+	}
+	model := &domain.CodeModel{MetaData: readModel(t, dir)}
+	svc := newTestFreshness(config)
+
+	spec := &domain.CodeSpec{}
+	spec.MetaData.LastModified = model.MetaData.Integrity.LastModified.Add(-24 * time.Hour)
+	f, err := svc.Check(ctx, dir, spec, model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Status != "fresh" || !reflect.DeepEqual(issueCodes(f), []string{domain.FreshnessSpecBehindModel}) {
+		t.Errorf("spec older than the sources: %+v", f)
+	} else if !reflect.DeepEqual(f.Issues[0].Files, []string{"a.go"}) {
+		t.Errorf("files = %v, want [a.go]", f.Issues[0].Files)
+	}
+
+	spec.MetaData.LastModified = model.MetaData.Integrity.LastModified
+	if f, _ = svc.Check(ctx, dir, spec, model); len(f.Issues) != 0 {
+		t.Errorf("spec as recent as the sources: %+v", f)
+	}
+}
+
+func TestFreshnessNamesManifestChanges(t *testing.T) {
+	config := domain.DefaultConfig()
+	config.Sync.Model.FileManifest = true
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.go"), "package m\n\nfunc A() {}\n")
+	writeFile(t, filepath.Join(dir, "b.go"), "package m\n\nfunc B() {}\n")
+	ctx := context.Background()
+	if _, err := newTestSync(config).Execute(ctx, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(dir, "a.go"), "package m\n\nfunc A() { C() }\n")
+	writeFile(t, filepath.Join(dir, "c.go"), "package m\n\nfunc C() {}\n")
+	if err := os.Remove(filepath.Join(dir, "b.go")); err != nil {
+		t.Fatal(err)
+	}
+	f, err := newTestFreshness(config).CheckModule(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []domain.FreshnessIssue{{
+		Code:     domain.FreshnessHashDrift,
+		Message:  "1 file(s) modified, 1 added, 1 removed since the last sync",
+		Files:    []string{"a.go", "b.go", "c.go"},
+		Added:    []string{"c.go"},
+		Removed:  []string{"b.go"},
+		Modified: []string{"a.go"},
+	}}
+	if f.Status != "stale" || !reflect.DeepEqual(f.Issues, want) {
+		t.Errorf("freshness:\n%+v\nwant issues\n%+v", f, want)
+	}
+}
+
+func TestFreshnessTouchesOnlyHashedFiles(t *testing.T) {
+	config := domain.DefaultConfig()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.go"), "package m\n\nfunc A() {}\n")
+	writeFile(t, filepath.Join(dir, "pkg", "b.go"), "package pkg\n\nfunc B() {}\n")
+	writeFile(t, filepath.Join(dir, "a_test.go"), "package m\n")
+	writeFile(t, filepath.Join(dir, ".env.go"), "package m\n")
+	writeFile(t, filepath.Join(dir, "vendor", "v.go"), "package v\n")
+	writeFile(t, filepath.Join(dir, "sub", "codespec.md"), "---\ntitle: Sub\n---\n")
+	writeFile(t, filepath.Join(dir, "sub", "s.go"), "package sub\n")
+	ctx := context.Background()
+	if _, err := newTestSync(config).Execute(ctx, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rel := range []string{"a.go", "pkg/b.go", "a_test.go", ".env.go", "vendor/v.go", "sub/s.go"} {
+		touch(t, filepath.Join(dir, filepath.FromSlash(rel)))
+	}
+	f, err := newTestFreshness(config).CheckModule(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Status != "fresh" || !reflect.DeepEqual(issueCodes(f), []string{domain.FreshnessMtimeDrift}) {
+		t.Fatalf("after touching: %+v", f)
+	}
+	if want := []string{"a.go", "pkg/b.go"}; !reflect.DeepEqual(f.Issues[0].Files, want) {
+		t.Errorf("touched files = %v, want %v", f.Issues[0].Files, want)
+	}
+}
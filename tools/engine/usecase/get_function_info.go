@@ -1,17 +1,44 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/Josepavese/asdp/engine/domain"
 )
 
 type FunctionInfoResponse struct {
-	Symbol  domain.Symbol          `json:"symbol"`
-	Code    string                 `json:"code"`
-	Context domain.ContextResponse `json:"context"`
+	Symbol     *domain.Symbol         `json:"symbol,omitempty"`
+	Code       string                 `json:"code,omitempty"`
+	DocComment string                 `json:"doc_comment,omitempty"` // With Details
+	Callers    []SymbolRef            `json:"callers,omitempty"`     // With Details: same-module symbols calling it
+	Callees    []SymbolRef            `json:"callees,omitempty"`     // With Details: same-module symbols it calls
+	Candidates []domain.Symbol        `json:"candidates,omitempty"`  // Set instead of Symbol when the selector is ambiguous
+	Context    domain.ContextResponse `json:"context"`
 }
 
+// SymbolRef points at another symbol of the same module.
+type SymbolRef struct {
+	Name   string `json:"name"`
+	Parent string `json:"parent,omitempty"`
+	Kind   string `json:"kind"`
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+}
+
+// FunctionInfoOptions narrow the symbol lookup and choose what is returned with it.
+type FunctionInfoOptions struct {
+	Kind    string // Optional: only symbols of this kind
+	Details bool   // Add the doc comment, callers and callees
+}
+
+// callableKinds are the symbol kinds that can call or be called, across parsers.
+var callableKinds = map[string]bool{"function": true, "method": true, "member": true}
+
 type GetFunctionInfoUseCase struct {
 	fs     domain.FileSystem
 	parser domain.ASTParser
@@ -28,7 +55,14 @@ func NewGetFunctionInfoUseCase(fs domain.FileSystem, parser domain.ASTParser, ha
 	}
 }
 
-func (uc *GetFunctionInfoUseCase) Execute(modulePath string, symbolName string) (*FunctionInfoResponse, error) {
+func (uc *GetFunctionInfoUseCase) Execute(ctx context.Context, modulePath string, symbolName string) (*FunctionInfoResponse, error) {
+	return uc.ExecuteWithOptions(ctx, modulePath, symbolName, FunctionInfoOptions{})
+}
+
+// ExecuteWithOptions looks up selector in the module's codemodel. The selector is a
+// symbol name, 'Parent.Name' (e.g. 'Server.Serve') or 'file:line' (the symbol whose
+// range contains that line). An ambiguous selector returns Candidates, not an error.
+func (uc *GetFunctionInfoUseCase) ExecuteWithOptions(ctx context.Context, modulePath string, selector string, opts FunctionInfoOptions) (*FunctionInfoResponse, error) {
 	absPath, err := validateAndExpandPath(modulePath)
 	if err != nil {
 		return nil, err
@@ -39,33 +73,213 @@ func (uc *GetFunctionInfoUseCase) Execute(modulePath string, symbolName string)
 	// We could instantiate QueryContextUseCase here or just reuse logic.
 	// Reusing logic is cleaner if we had a service, but let's keep it simple.
 	queryUC := NewQueryContextUseCase(uc.fs, uc.hasher, uc.config)
-	ctx, err := queryUC.Execute(modulePath)
+	moduleCtx, err := queryUC.Execute(ctx, modulePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get module context: %w", err)
 	}
 
 	// 2. Find symbol in model
-	var targetSymbol *domain.Symbol
-	for _, sym := range ctx.Model.MetaData.Symbols {
-		if sym.Name == symbolName {
-			targetSymbol = &sym
-			break
-		}
+	symbols := moduleCtx.Model.MetaData.Symbols
+	matches := selectSymbols(symbols, selector, opts.Kind)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("symbol %s not found in module %s", selector, modulePath)
 	}
-
-	if targetSymbol == nil {
-		return nil, fmt.Errorf("symbol %s not found in module %s", symbolName, modulePath)
+	if len(matches) > 1 {
+		return &FunctionInfoResponse{Candidates: matches, Context: *moduleCtx}, nil
 	}
+	targetSymbol := matches[0]
 
 	// 3. Extract body
-	body, err := uc.parser.GetSymbolBody(modulePath, *targetSymbol)
+	body, err := uc.parser.GetSymbolBody(modulePath, targetSymbol)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract symbol body: %w", err)
 	}
 
-	return &FunctionInfoResponse{
-		Symbol:  *targetSymbol,
+	resp := &FunctionInfoResponse{
+		Symbol:  &targetSymbol,
 		Code:    body,
-		Context: *ctx,
-	}, nil
+		Context: *moduleCtx,
+	}
+	if opts.Details {
+		uc.addDetails(modulePath, resp, symbols)
+	}
+	return resp, nil
+}
+
+// selectSymbols returns every symbol matched by selector. A 'file:line' selector keeps
+// only the innermost symbols containing the line, so a method wins over its class.
+func selectSymbols(symbols []domain.Symbol, selector string, kind string) []domain.Symbol {
+	var matches []domain.Symbol
+
+	if i := strings.LastIndex(selector, ":"); i > 0 {
+		if line, err := strconv.Atoi(selector[i+1:]); err == nil {
+			file := filepath.ToSlash(filepath.Clean(selector[:i]))
+			span := -1
+			for _, sym := range symbols {
+				if kind != "" && sym.Kind != kind {
+					continue
+				}
+				if !sameFile(filepath.ToSlash(sym.FilePath), file) || line < sym.Line || line > sym.LineEnd {
+					continue
+				}
+				switch s := sym.LineEnd - sym.Line; {
+				case span < 0 || s < span:
+					span, matches = s, []domain.Symbol{sym}
+				case s == span:
+					matches = append(matches, sym)
+				}
+			}
+			return matches
+		}
+	}
+
+	parent, name := "", selector
+	if i := strings.LastIndex(selector, "."); i > 0 && i < len(selector)-1 {
+		parent, name = selector[:i], selector[i+1:]
+	}
+	for _, sym := range symbols {
+		if kind != "" && sym.Kind != kind {
+			continue
+		}
+		if sym.Name == selector || (parent != "" && sym.Name == name && strings.TrimPrefix(sym.Parent, "*") == parent) {
+			matches = append(matches, sym)
+		}
+	}
+	return matches
+}
+
+// sameFile matches a module-relative path against a selector file, which may be
+// given as a path suffix (e.g. 'server.go' for 'internal/server.go').
+func sameFile(symFile, file string) bool {
+	return symFile == file || strings.HasSuffix(symFile, "/"+file)
+}
+
+// addDetails fills the doc comment and the same-module callers and callees of resp.Symbol:
+// from the call graph of a codemodel that records one (Go sources), or else found
+// textually ('Name(' in a body), so that same-named symbols are not told apart.
+func (uc *GetFunctionInfoUseCase) addDetails(modulePath string, resp *FunctionInfoResponse, symbols []domain.Symbol) {
+	target := *resp.Symbol
+	files := make(map[string][]string)
+	file := func(path string) []string {
+		content, ok := files[path]
+		if !ok {
+			if data, err := uc.fs.ReadFile(filepath.Join(modulePath, path)); err == nil {
+				content = strings.Split(string(data), "\n")
+			}
+			files[path] = content
+		}
+		return content
+	}
+	lines := func(sym domain.Symbol) []string {
+		content := file(sym.FilePath)
+		if sym.Line <= 0 || sym.Line > len(content) {
+			return nil
+		}
+		end := sym.LineEnd
+		if end > len(content) || end < sym.Line {
+			end = len(content)
+		}
+		return content[sym.Line-1 : end]
+	}
+
+	resp.DocComment = leadingComment(file(target.FilePath), target.Line)
+
+	if !callableKinds[target.Kind] {
+		return
+	}
+	if g := newCallGraph(symbols); g.recorded && strings.HasSuffix(target.FilePath, ".go") {
+		start := g.indexOf(target)
+		for _, n := range g.walk(start, g.callers, 1) {
+			resp.Callers = append(resp.Callers, n.SymbolRef)
+		}
+		for _, n := range g.walk(start, g.callees, 1) {
+			resp.Callees = append(resp.Callees, n.SymbolRef)
+		}
+		return
+	}
+
+	// calls reports whether body, the body of symbol owner, calls name
+	calls := func(body []string, owner, name string) bool {
+		for i, l := range body {
+			if i == 0 {
+				l = strings.Replace(l, owner, "", 1) // Skip the declaration itself
+			}
+			if callsName(l, name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	targetBody := lines(target)
+	for _, sym := range symbols {
+		if !callableKinds[sym.Kind] || (sym.Name == target.Name && sym.Parent == target.Parent && sym.FilePath == target.FilePath && sym.Line == target.Line) {
+			continue
+		}
+		ref := SymbolRef{Name: sym.Name, Parent: sym.Parent, Kind: sym.Kind, File: filepath.ToSlash(sym.FilePath), Line: sym.Line}
+		if calls(lines(sym), sym.Name, target.Name) {
+			resp.Callers = append(resp.Callers, ref)
+		}
+		if calls(targetBody, target.Name, sym.Name) {
+			resp.Callees = append(resp.Callees, ref)
+		}
+	}
+	sort.SliceStable(resp.Callers, func(i, j int) bool { return resp.Callers[i].Name < resp.Callers[j].Name })
+	sort.SliceStable(resp.Callees, func(i, j int) bool { return resp.Callees[i].Name < resp.Callees[j].Name })
+}
+
+// callsName reports whether line holds name as a whole word followed by an opening
+// parenthesis, as in a call.
+func callsName(line, name string) bool {
+	if name == "" {
+		return false
+	}
+	for from := 0; ; {
+		i := strings.Index(line[from:], name)
+		if i < 0 {
+			return false
+		}
+		i += from
+		from = i + len(name)
+		if i > 0 && isIdentByte(line[i-1]) {
+			continue
+		}
+		if strings.HasPrefix(strings.TrimLeft(line[from:], " \t"), "(") {
+			return true
+		}
+	}
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// leadingComment returns the comment block directly above line (1-based), without markers.
+func leadingComment(content []string, line int) string {
+	var block []string
+	for i := line - 2; i >= 0 && i < len(content); i-- {
+		l := strings.TrimSpace(content[i])
+		if !isCommentLine(l) {
+			break
+		}
+		block = append([]string{l}, block...)
+	}
+	for i, l := range block {
+		for _, marker := range []string{"///", "//", "/**", "/*", "*/", "*", "#"} {
+			l = strings.TrimSuffix(strings.TrimPrefix(l, marker), "*/")
+		}
+		block[i] = strings.TrimSpace(l)
+	}
+	return strings.TrimSpace(strings.Join(block, "\n"))
+}
+
+// isCommentLine reports whether a trimmed line is a comment, or the continuation of a
+// block comment ("* text"), as opposed to a statement such as "*p = x".
+func isCommentLine(l string) bool {
+	for _, p := range []string{"//", "#", "/*", "* ", "*/"} {
+		if strings.HasPrefix(l, p) {
+			return true
+		}
+	}
+	return l == "*"
 }
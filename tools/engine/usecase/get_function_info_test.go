@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Josepavese/asdp/engine/domain"
+	"github.com/Josepavese/asdp/engine/system"
+)
+
+func TestCallsName(t *testing.T) {
+	tests := []struct {
+		line, name string
+		want       bool
+	}{
+		{"\treturn load(path)", "load", true},
+		{"x := s.Load (path)", "Load", true},
+		{"preload(path)", "load", false},
+		{"loader(path)", "load", false},
+		{"var load = 1", "load", false},
+		{"a(); load()", "load", true},
+		{"$load()", "load", false},
+	}
+	for _, tt := range tests {
+		if got := callsName(tt.line, tt.name); got != tt.want {
+			t.Errorf("callsName(%q, %q) = %v, want %v", tt.line, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLeadingComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []string
+		want    string
+	}{
+		{"line comments", []string{"// Load reads", "// the config.", "func Load() {"}, "Load reads\nthe config."},
+		{"block comment", []string{"/**", " * Adds numbers.", " */", "function add() {"}, "Adds numbers."},
+		{"dereference above", []string{"\t*p = x", "func f() {"}, ""},
+		{"no comment", []string{"x := 1", "func f() {"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := leadingComment(tt.content, len(tt.content)); got != tt.want {
+				t.Errorf("leadingComment = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDetailsFromRecordedCalls checks that the callers and callees come from the call
+// graph when the codemodel records one, even where the text suggests otherwise.
+func TestDetailsFromRecordedCalls(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.go"), "package m\n\nfunc A() { B() }\n\nfunc B() {}\n\nfunc C() {\n\t// B() is not called here\n}\n")
+	symbols := []domain.Symbol{
+		{Name: "A", Kind: "function", FilePath: "a.go", Line: 3, LineEnd: 3, Calls: []string{"B"}},
+		{Name: "B", Kind: "function", FilePath: "a.go", Line: 5, LineEnd: 5},
+		{Name: "C", Kind: "function", FilePath: "a.go", Line: 7, LineEnd: 9},
+	}
+	uc := &GetFunctionInfoUseCase{fs: system.NewRealFileSystem()}
+	resp := &FunctionInfoResponse{Symbol: &symbols[1]}
+	uc.addDetails(dir, resp, symbols)
+
+	want := []SymbolRef{{Name: "A", Kind: "function", File: "a.go", Line: 3}}
+	if !reflect.DeepEqual(resp.Callers, want) {
+		t.Errorf("callers of B = %+v, want %+v", resp.Callers, want)
+	}
+	if len(resp.Callees) != 0 {
+		t.Errorf("callees of B = %+v, want none", resp.Callees)
+	}
+}
@@ -2,7 +2,6 @@ package usecase
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/Josepavese/asdp/engine/domain"
@@ -25,11 +24,10 @@ func (uc *InitAgentUseCase) Execute(projectPath string) (string, error) {
 	projectPath = absPath
 
 	// 1. Resolve source directory from Config
-	home, err := os.UserHomeDir()
+	srcDir, err := globalAssetsDir(uc.config)
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
 	}
-	srcDir := filepath.Join(home, uc.config.System.GlobalAssetsDir)
 
 	// Verify source exists
 	if _, err := uc.fs.Stat(srcDir); err != nil {
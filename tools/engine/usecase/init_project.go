@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -18,14 +19,14 @@ func NewInitProjectUseCase(fs *InitAgentUseCase, syncTree *SyncTreeUseCase, scaf
 	}
 }
 
-func (uc *InitProjectUseCase) Execute(projectPath, codePath, title, summary, context string) (string, error) {
+func (uc *InitProjectUseCase) Execute(ctx context.Context, projectPath, codePath, title, summary, moduleContext string) (string, error) {
 	if len(title) < 3 {
 		return "", fmt.Errorf("title must be at least 3 characters long")
 	}
 	if len(summary) < 10 {
 		return "", fmt.Errorf("summary must be at least 10 characters long")
 	}
-	if len(context) < 20 {
+	if len(moduleContext) < 20 {
 		return "", fmt.Errorf("context must be at least 20 characters long")
 	}
 
@@ -41,7 +42,7 @@ func (uc *InitProjectUseCase) Execute(projectPath, codePath, title, summary, con
 		codePath = projectPath
 	}
 
-	tree, err := uc.syncTree.Execute(codePath)
+	tree, err := uc.syncTree.Execute(ctx, codePath)
 	if err != nil {
 		return "", fmt.Errorf("failed to sync codetree: %w", err)
 	}
@@ -54,7 +55,7 @@ func (uc *InitProjectUseCase) Execute(projectPath, codePath, title, summary, con
 		Type:    "module",
 		Title:   title,
 		Summary: summary,
-		Context: context,
+		Context: moduleContext,
 	})
 	if err != nil {
 		// Log but don't fail if scaffold fails (e.g. files already exist)
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -21,7 +22,7 @@ func NewManageExclusionsUseCase(fs domain.FileSystem, syncTreeUC *SyncTreeUseCas
 	}
 }
 
-func (uc *ManageExclusionsUseCase) Execute(projectPath string, target string, action string) error {
+func (uc *ManageExclusionsUseCase) Execute(ctx context.Context, projectPath string, target string, action string) error {
 	absPath, err := validateAndExpandPath(projectPath)
 	if err != nil {
 		return err
@@ -94,7 +95,7 @@ func (uc *ManageExclusionsUseCase) Execute(projectPath string, target string, ac
 	}
 
 	// Trigger SyncTree to refresh the view immediately with new exclusions
-	_, err = uc.syncTreeUC.Execute(projectPath)
+	_, err = uc.syncTreeUC.Execute(ctx, projectPath)
 	if err != nil {
 		return fmt.Errorf("exclusions saved but failed to refresh tree: %w", err)
 	}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// ModuleArtifactsUseCase exposes the parsed ASDP artifacts (codespec, codemodel, codetree)
+// of a project, for adapters that publish them as documents rather than tool results.
+type ModuleArtifactsUseCase struct {
+	fs domain.FileSystem
+}
+
+func NewModuleArtifactsUseCase(fs domain.FileSystem) *ModuleArtifactsUseCase {
+	return &ModuleArtifactsUseCase{fs: fs}
+}
+
+// ModuleRef describes a module listed in the project codetree.
+type ModuleRef struct {
+	Path        string `json:"path"`     // Absolute path
+	RelPath     string `json:"rel_path"` // Relative to the project root, "" for the root itself
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	HasSpec     bool   `json:"has_spec"`
+	HasModel    bool   `json:"has_model"`
+}
+
+// ListModules returns every module of the codetree.md at root, the root first.
+func (uc *ModuleArtifactsUseCase) ListModules(ctx context.Context, root string) ([]ModuleRef, error) {
+	absPath, err := validateAndExpandPath(root)
+	if err != nil {
+		return nil, err
+	}
+	root = absPath
+
+	modules, err := listTreeModules(uc.fs, root)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]ModuleRef, 0, len(modules))
+	for _, m := range modules {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		rel, _ := filepath.Rel(root, m.Path)
+		if rel == "." {
+			rel = ""
+		}
+		refs = append(refs, ModuleRef{
+			Path:        m.Path,
+			RelPath:     filepath.ToSlash(rel),
+			Name:        m.Component.Name,
+			Description: m.Component.Description,
+			HasSpec:     m.Component.HasSpec,
+			HasModel:    m.Component.HasModel,
+		})
+	}
+	return refs, nil
+}
+
+// Spec reads and parses the codespec.md of the module at path.
+func (uc *ModuleArtifactsUseCase) Spec(path string) (*domain.CodeSpec, error) {
+	absPath, err := validateAndExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := uc.fs.ReadFile(filepath.Join(absPath, "codespec.md"))
+	if err != nil {
+		return nil, fmt.Errorf("codespec.md not found in %s", absPath)
+	}
+	spec, err := parseCodeSpec(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse codespec.md: %w", err)
+	}
+	if spec == nil {
+		return nil, fmt.Errorf("codespec.md in %s has invalid format (missing frontmatter delimiters)", absPath)
+	}
+	return spec, nil
+}
+
+// Model reads and parses the codemodel.md of the module at path.
+func (uc *ModuleArtifactsUseCase) Model(path string) (*domain.CodeModel, error) {
+	absPath, err := validateAndExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := uc.fs.ReadFile(filepath.Join(absPath, "codemodel.md"))
+	if err != nil {
+		return nil, fmt.Errorf("codemodel.md not found in %s", absPath)
+	}
+	model, err := parseCodeModel(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse codemodel.md: %w", err)
+	}
+	return model, nil
+}
+
+// Tree reads and parses the codetree.md at the project root.
+func (uc *ModuleArtifactsUseCase) Tree(root string) (*domain.CodeTree, error) {
+	absPath, err := validateAndExpandPath(root)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := uc.fs.ReadFile(filepath.Join(absPath, "codetree.md"))
+	if err != nil {
+		return nil, fmt.Errorf("codetree.md not found in %s", absPath)
+	}
+	tree, err := parseCodeTree(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse codetree.md: %w", err)
+	}
+	return tree, nil
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/Josepavese/asdp/engine/domain"
 	"gopkg.in/yaml.v3"
@@ -10,7 +11,94 @@ import (
 
 // Shared helpers to parse Frontmatter
 
+// parseMemoSize bounds each memo; a full memo is simply emptied.
+const parseMemoSize = 4096
+
+// parseMemo remembers parsed artifacts by content: the long-lived server would otherwise
+// parse the same codespec.md, codemodel.md and codetree.md on every call. Since the content
+// is the key, there is nothing to invalidate.
+type parseMemo[T any] struct {
+	mu      sync.Mutex
+	entries map[string]T
+}
+
+func (m *parseMemo[T]) get(data []byte) (T, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.entries[string(data)]
+	return v, ok
+}
+
+func (m *parseMemo[T]) put(data []byte, v T) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.entries == nil || len(m.entries) >= parseMemoSize {
+		m.entries = make(map[string]T)
+	}
+	m.entries[string(data)] = v
+}
+
+var (
+	specMemo  parseMemo[domain.CodeSpec]
+	modelMemo parseMemo[domain.CodeModel]
+	treeMemo  parseMemo[domain.CodeTree]
+)
+
+// Callers may modify what they get back, so memoized values are returned as deep copies
+// that share no slice or map with the memo.
+
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
+
+func cloneSpec(spec domain.CodeSpec) *domain.CodeSpec {
+	spec.MetaData.Capabilities = cloneSlice(spec.MetaData.Capabilities)
+	spec.MetaData.Dependencies = cloneSlice(spec.MetaData.Dependencies)
+	spec.MetaData.Requirements = cloneSlice(spec.MetaData.Requirements)
+	spec.MetaData.Exports = cloneSlice(spec.MetaData.Exports)
+	return &spec
+}
+
+func cloneModel(model domain.CodeModel) *domain.CodeModel {
+	model.MetaData.Symbols = cloneSlice(model.MetaData.Symbols)
+	for i := range model.MetaData.Symbols {
+		sym := &model.MetaData.Symbols[i]
+		sym.Decorators = cloneSlice(sym.Decorators)
+		sym.Imports = cloneSlice(sym.Imports)
+		sym.References = cloneSlice(sym.References)
+		sym.Implements = cloneSlice(sym.Implements)
+		sym.Calls = cloneSlice(sym.Calls)
+	}
+	if files := model.MetaData.Integrity.Files; files != nil {
+		model.MetaData.Integrity.Files = make(map[string]string, len(files))
+		for path, hash := range files {
+			model.MetaData.Integrity.Files[path] = hash
+		}
+	}
+	return &model
+}
+
+func cloneTree(tree domain.CodeTree) *domain.CodeTree {
+	tree.MetaData.Components = cloneComponents(tree.MetaData.Components)
+	tree.MetaData.Excludes = cloneSlice(tree.MetaData.Excludes)
+	return &tree
+}
+
+func cloneComponents(comps []domain.Component) []domain.Component {
+	out := cloneSlice(comps)
+	for i := range out {
+		out[i].Children = cloneComponents(out[i].Children)
+	}
+	return out
+}
+
 func parseCodeSpec(data []byte) (*domain.CodeSpec, error) {
+	if spec, ok := specMemo.get(data); ok {
+		return cloneSpec(spec), nil
+	}
 	// Simple split by "---"
 	parts := strings.SplitN(string(data), "---", 3)
 	if len(parts) < 3 {
@@ -25,10 +113,12 @@ func parseCodeSpec(data []byte) (*domain.CodeSpec, error) {
 		return nil, err
 	}
 
-	return &domain.CodeSpec{
+	spec := domain.CodeSpec{
 		MetaData: meta,
 		Body:     body,
-	}, nil
+	}
+	specMemo.put(data, spec)
+	return cloneSpec(spec), nil
 }
 
 // Basic structural check only
@@ -38,6 +128,9 @@ func IsSpecParsable(data []byte) bool {
 }
 
 func parseCodeModel(data []byte) (*domain.CodeModel, error) {
+	if model, ok := modelMemo.get(data); ok {
+		return cloneModel(model), nil
+	}
 	parts := strings.SplitN(string(data), "---", 3)
 	if len(parts) < 3 {
 		return nil, fmt.Errorf("invalid format")
@@ -51,8 +144,32 @@ func parseCodeModel(data []byte) (*domain.CodeModel, error) {
 		return nil, err
 	}
 
-	return &domain.CodeModel{
+	model := domain.CodeModel{
 		MetaData: meta,
 		Body:     body,
-	}, nil
+	}
+	modelMemo.put(data, model)
+	return cloneModel(model), nil
+}
+
+func parseCodeTree(data []byte) (*domain.CodeTree, error) {
+	if tree, ok := treeMemo.get(data); ok {
+		return cloneTree(tree), nil
+	}
+	parts := strings.SplitN(string(data), "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid format")
+	}
+
+	var meta domain.CodeTreeMeta
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		return nil, err
+	}
+
+	tree := domain.CodeTree{
+		MetaData: meta,
+		Body:     parts[2],
+	}
+	treeMemo.put(data, tree)
+	return cloneTree(tree), nil
 }
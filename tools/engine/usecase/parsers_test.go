@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParsedModelSharesNothingWithMemo edits every slice and map of a parsed codemodel
+// in place and parses the same content again.
+func TestParsedModelSharesNothingWithMemo(t *testing.T) {
+	data := []byte(`---
+asdp_version: 0.1.0
+integrity:
+    src_hash: abc
+    files:
+        a.go: "1"
+symbols:
+    - name: A
+      kind: function
+      decorators: [d]
+      imports: [fmt]
+      references: [fmt.Println]
+      implements: [I]
+      calls: [B]
+---
+body
+`)
+	first, err := parseCodeModel(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sym := &first.MetaData.Symbols[0]
+	for _, s := range [][]string{sym.Decorators, sym.Imports, sym.References, sym.Implements, sym.Calls} {
+		s[0] = "changed"
+	}
+	first.MetaData.Integrity.Files["a.go"] = "changed"
+	first.MetaData.Symbols[0].Name = "changed"
+
+	second, _ := parseCodeModel(data)
+	got := second.MetaData.Symbols[0]
+	if got.Name != "A" || second.MetaData.Integrity.Files["a.go"] != "1" {
+		t.Errorf("the memo was modified through a parsed model: %+v", second.MetaData)
+	}
+	for _, s := range [][]string{got.Decorators, got.Imports, got.References, got.Implements, got.Calls} {
+		if reflect.DeepEqual(s, []string{"changed"}) {
+			t.Errorf("the memo was modified through a parsed model: %+v", got)
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package usecase
 
 import (
-	"fmt"
+	"context"
 	"path/filepath"
 
 	"github.com/Josepavese/asdp/engine/domain"
@@ -11,49 +11,99 @@ type QueryContextUseCase struct {
 	fs     domain.FileSystem
 	hasher domain.ContentHasher
 	config domain.Config
+
+	freshness *FreshnessService
 }
 
 func NewQueryContextUseCase(fs domain.FileSystem, hasher domain.ContentHasher, config domain.Config) *QueryContextUseCase {
-	return &QueryContextUseCase{fs: fs, hasher: hasher, config: config}
+	return &QueryContextUseCase{fs: fs, hasher: hasher, config: config, freshness: NewFreshnessService(fs, hasher, config)}
 }
 
 // ContextResponse moved to domain
 
-func (uc *QueryContextUseCase) Execute(path string) (*domain.ContextResponse, error) {
+// Sections a context query can include for each module.
+const (
+	IncludeSpec      = "spec"
+	IncludeModel     = "model"
+	IncludeFreshness = "freshness"
+)
+
+// QueryOptions widens a context query from one module to its subtree.
+type QueryOptions struct {
+	Depth     int      // Levels of child modules to nest; 0 returns the module alone
+	Include   []string // Sections per node (IncludeSpec, IncludeModel, IncludeFreshness); empty means all
+	MaxTokens int      // Estimated token budget for the whole response; 0 means unbounded
+}
+
+func (o QueryOptions) includes(section string) bool {
+	if len(o.Include) == 0 {
+		return true
+	}
+	for _, s := range o.Include {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+func (uc *QueryContextUseCase) Execute(ctx context.Context, path string) (*domain.ContextResponse, error) {
+	return uc.ExecuteWithOptions(ctx, path, QueryOptions{})
+}
+
+// ExecuteWithOptions returns the context of the module at path and, down to opts.Depth,
+// of its child modules, each with its own freshness.
+func (uc *QueryContextUseCase) ExecuteWithOptions(ctx context.Context, path string, opts QueryOptions) (*domain.ContextResponse, error) {
 	absPath, err := validateAndExpandPath(path)
 	if err != nil {
 		return nil, err
 	}
-	path = absPath
+	resp, err := uc.queryModule(ctx, absPath, opts, opts.Depth)
+	if err != nil || opts.MaxTokens <= 0 {
+		return resp, err
+	}
+	return newContextPacker(opts.MaxTokens).pack(resp), nil
+}
 
+func (uc *QueryContextUseCase) queryModule(ctx context.Context, path string, opts QueryOptions, depth int) (*domain.ContextResponse, error) {
 	resp := &domain.ContextResponse{
 		Path:      path,
 		Freshness: domain.Freshness{Status: "unknown"},
 	}
 
 	// 1. Read CodeSpec
+	var spec *domain.CodeSpec
 	specBytes, err := uc.fs.ReadFile(filepath.Join(path, "codespec.md"))
 	if err == nil {
-		spec, err := parseCodeSpec(specBytes)
-		if err == nil {
-			resp.Spec = *spec
+		if spec, err = parseCodeSpec(specBytes); err == nil {
 			resp.Summary = spec.MetaData.Summary
-			resp.Spec.Body = ""
+			if opts.includes(IncludeSpec) {
+				resp.Spec = *spec
+				if opts.MaxTokens <= 0 {
+					resp.Spec.Body = "" // The packer decides when a budget is given
+				}
+			}
 
 			// Policy-based validation is now centralized in ValidateProjectUseCase
 			// We only do a basic structural check here if needed.
 		}
 	}
 
-	// 2. Read CodeModel
+	// 2. Read CodeModel (always: freshness compares against its hash)
+	var model *domain.CodeModel
 	modelBytes, err := uc.fs.ReadFile(filepath.Join(path, "codemodel.md"))
 	if err == nil {
-		model, err := parseCodeModel(modelBytes)
-		if err == nil {
-			resp.Model = *model
-			resp.Model.Body = ""
+		if parsed, err := parseCodeModel(modelBytes); err == nil {
+			model = parsed
+		}
+	}
+	if model != nil && opts.includes(IncludeModel) {
+		resp.Model = *model
+		resp.Model.Body = ""
+		resp.Model.MetaData.Integrity.Files = nil // Freshness reports what changed
 
-			// Optimize Payload: Strip docstrings to prevent JSON truncation
+		// Optimize Payload: Strip docstrings to prevent JSON truncation (unless a budget decides)
+		if opts.MaxTokens <= 0 {
 			for i := range resp.Model.MetaData.Symbols {
 				resp.Model.MetaData.Symbols[i].Docstring = ""
 			}
@@ -61,27 +111,30 @@ func (uc *QueryContextUseCase) Execute(path string) (*domain.ContextResponse, er
 	}
 
 	// 3. Check Freshness
-	if resp.Model.MetaData.ASDPVersion != "" {
-		realHash, err := uc.hasher.HashDir(path)
-		if err == nil {
-			docHash := resp.Model.MetaData.Integrity.SrcHash
-			resp.Freshness.CurrentHash = realHash
-			resp.Freshness.DocHash = docHash
-
-			if docHash == uc.config.Sync.Model.FirstSyncHash {
-				resp.Freshness.Status = "stale"
-				resp.Freshness.Reason = "New module, never synced"
-			} else if docHash != realHash {
-				resp.Freshness.Status = "stale"
-				resp.Freshness.Reason = "Source code changed"
-			} else {
-				resp.Freshness.Status = "fresh"
-			}
-		} else {
-			resp.Freshness.Reason = fmt.Sprintf("Hashing failed: %v", err)
-		}
+	if !opts.includes(IncludeFreshness) {
+		resp.Freshness.Reason = "Not requested"
 	} else {
-		resp.Freshness.Reason = "No codemodel.md found"
+		freshness, err := uc.freshness.Check(ctx, path, spec, model)
+		if err != nil {
+			return nil, err
+		}
+		resp.Freshness = freshness
+	}
+
+	// 4. Nest child modules
+	if depth <= 0 {
+		return resp, nil
+	}
+	children, err := childModules(ctx, uc.fs, uc.config, path)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		childResp, err := uc.queryModule(ctx, child, opts, depth-1)
+		if err != nil {
+			return nil, err
+		}
+		resp.Children = append(resp.Children, *childResp)
 	}
 
 	return resp, nil
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Josepavese/asdp/engine/domain"
+	"github.com/Josepavese/asdp/engine/system"
+)
+
+// queryContextProject lays out a root module with api and pkg/store (behind a plain
+// directory) below it, cache nested in store, and modules in an ignored and a hidden
+// directory. Every module is synced; api's source then changes.
+func queryContextProject(t *testing.T, config *domain.Config) string {
+	root := t.TempDir()
+	modules := []string{".", "api", filepath.Join("pkg", "store"), filepath.Join("pkg", "store", "cache")}
+	for _, m := range modules {
+		dir := filepath.Join(root, m)
+		writeFile(t, filepath.Join(dir, "codespec.md"), "---\ntitle: "+filepath.Base(dir)+"\nsummary: The "+filepath.ToSlash(m)+" module\n---\nBody\n")
+		writeFile(t, filepath.Join(dir, "m.go"), "package m\n\nfunc F() {}\n")
+	}
+	writeFile(t, filepath.Join(root, "node_modules", "lib", "codespec.md"), "---\ntitle: lib\n---\n")
+	writeFile(t, filepath.Join(root, ".hidden", "codespec.md"), "---\ntitle: hidden\n---\n")
+
+	ctx := context.Background()
+	for _, m := range modules {
+		if _, err := newTestSync(config).Execute(ctx, filepath.Join(root, m)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(root, "api", "m.go"), "package m\n\nfunc F() { F() }\n")
+	touch(t, filepath.Join(root, "api", "m.go"))
+	return root
+}
+
+func newTestQueryContext(config *domain.Config) *QueryContextUseCase {
+	return NewQueryContextUseCase(system.NewRealFileSystem(), system.NewSHA256ContentHasher(config.Hasher), *config)
+}
+
+// contextTree lists each node of resp as "path summary status", indented by depth.
+func contextTree(root string, resp *domain.ContextResponse, indent string) []string {
+	rel, _ := filepath.Rel(root, resp.Path)
+	lines := []string{indent + filepath.ToSlash(rel) + " " + resp.Freshness.Status}
+	for i := range resp.Children {
+		lines = append(lines, contextTree(root, &resp.Children[i], indent+"  ")...)
+	}
+	return lines
+}
+
+func TestQueryContextDepth(t *testing.T) {
+	config := domain.DefaultConfig()
+	root := queryContextProject(t, config)
+	uc := newTestQueryContext(config)
+
+	tests := []struct {
+		depth int
+		want  []string
+	}{
+		{0, []string{". fresh"}},
+		{1, []string{". fresh", "  api stale", "  pkg/store fresh"}},
+		{2, []string{". fresh", "  api stale", "  pkg/store fresh", "    pkg/store/cache fresh"}},
+	}
+	for _, tt := range tests {
+		resp, err := uc.ExecuteWithOptions(context.Background(), root, QueryOptions{Depth: tt.depth})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := contextTree(root, resp, ""); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("depth %d:\n%v\nwant\n%v", tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestQueryContextInclude(t *testing.T) {
+	config := domain.DefaultConfig()
+	root := queryContextProject(t, config)
+	uc := newTestQueryContext(config)
+
+	tests := []struct {
+		include                []string
+		spec, model, freshness bool
+	}{
+		{nil, true, true, true},
+		{[]string{IncludeModel}, false, true, false},
+		{[]string{IncludeSpec, IncludeFreshness}, true, false, true},
+	}
+	for _, tt := range tests {
+		resp, err := uc.ExecuteWithOptions(context.Background(), root, QueryOptions{Depth: 2, Include: tt.include})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var check func(n *domain.ContextResponse)
+		check = func(n *domain.ContextResponse) {
+			rel, _ := filepath.Rel(root, n.Path)
+			if want := "The " + filepath.ToSlash(rel) + " module"; n.Summary != want {
+				t.Errorf("%v %s: summary %q, want %q", tt.include, rel, n.Summary, want)
+			}
+			if got := n.Spec.MetaData.Title != ""; got != tt.spec {
+				t.Errorf("%v %s: spec included %v, want %v", tt.include, rel, got, tt.spec)
+			}
+			if got := len(n.Model.MetaData.Symbols) > 0; got != tt.model {
+				t.Errorf("%v %s: model included %v, want %v", tt.include, rel, got, tt.model)
+			}
+			if got := n.Freshness.Status != "unknown"; got != tt.freshness {
+				t.Errorf("%v %s: freshness %+v, want included %v", tt.include, rel, n.Freshness, tt.freshness)
+			}
+			for i := range n.Children {
+				check(&n.Children[i])
+			}
+		}
+		check(resp)
+		if len(resp.Children) != 2 || len(resp.Children[1].Children) != 1 {
+			t.Errorf("%v: children not nested to depth 2", tt.include)
+		}
+	}
+}
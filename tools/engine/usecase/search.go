@@ -0,0 +1,436 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// SearchUseCase answers full-text queries over the prose of every codespec.md and
+// codemodel.md of the codetree. The inverted index is kept in memory, persisted under
+// <root>/<System.CacheDir>, and brought up to date on each search by re-indexing only
+// the files whose modification time changed.
+type SearchUseCase struct {
+	fs     domain.FileSystem
+	config domain.Config
+
+	mu      sync.Mutex
+	indexes map[string]*searchIndex // By project root
+}
+
+func NewSearchUseCase(fs domain.FileSystem, config domain.Config) *SearchUseCase {
+	return &SearchUseCase{fs: fs, config: config, indexes: make(map[string]*searchIndex)}
+}
+
+// Fields a hit can come from.
+const (
+	SearchFieldTitle       = "title"
+	SearchFieldSummary     = "summary"
+	SearchFieldCapability  = "capability"
+	SearchFieldRequirement = "requirement" // Section is the requirement id
+	SearchFieldSpecBody    = "spec_body"   // Section is the markdown heading
+	SearchFieldAnnotation  = "annotation"  // codemodel.md body; Section is the markdown heading
+)
+
+// searchFieldWeights rank a term found in a title above the same term in a body.
+var searchFieldWeights = map[string]float64{
+	SearchFieldTitle:       3,
+	SearchFieldSummary:     2,
+	SearchFieldCapability:  2,
+	SearchFieldRequirement: 1.5,
+	SearchFieldSpecBody:    1,
+	SearchFieldAnnotation:  1,
+}
+
+const (
+	searchIndexFile    = "search-index.json"
+	searchIndexVersion = 1 // Bump when the persisted layout or the tokenizer changes
+	defaultSearchLimit = 20
+	snippetRadius      = 80
+)
+
+var searchStopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "be": true, "by": true, "for": true,
+	"in": true, "is": true, "it": true, "of": true, "on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+type SearchHit struct {
+	Module  string  `json:"module"` // Relative to the project root, "." for the root
+	File    string  `json:"file"`   // Relative to the project root
+	Field   string  `json:"field"`
+	Section string  `json:"section,omitempty"`
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+type SearchResponse struct {
+	Query     string      `json:"query"`
+	Total     int         `json:"total"`     // Hits before Limit
+	Truncated bool        `json:"truncated"` // Total > len(Hits)
+	Reindexed int         `json:"reindexed"` // Files (re)indexed by this search
+	Hits      []SearchHit `json:"hits"`
+}
+
+// searchUnit is the smallest searchable piece: a title, a requirement, a body section...
+type searchUnit struct {
+	Field   string         `json:"field"`
+	Section string         `json:"section,omitempty"`
+	Text    string         `json:"text"`
+	Terms   map[string]int `json:"terms"`
+}
+
+type searchDoc struct {
+	Module string       `json:"module"`
+	Stamp  int64        `json:"stamp"` // Modification time (ns) when indexed
+	Units  []searchUnit `json:"units"`
+}
+
+type unitRef struct {
+	file string
+	unit int
+}
+
+type searchIndex struct {
+	Version int                   `json:"version"`
+	Docs    map[string]*searchDoc `json:"docs"` // By file, relative to the project root
+
+	// Derived from Docs by build
+	postings map[string]map[unitRef]int // term -> unit -> term frequency
+	lengths  map[unitRef]int
+	avgLen   float64
+}
+
+func (uc *SearchUseCase) Execute(ctx context.Context, root string, query string, limit int) (*SearchResponse, error) {
+	absPath, err := validateAndExpandPath(root)
+	if err != nil {
+		return nil, err
+	}
+	root = absPath
+
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("query must contain at least one searchable word")
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	idx := uc.indexes[root]
+	if idx == nil {
+		idx = uc.load(root)
+		uc.indexes[root] = idx
+	}
+	reindexed, changed, err := uc.refresh(ctx, root, idx)
+	if err != nil {
+		return nil, err
+	}
+	if changed || idx.postings == nil {
+		idx.build()
+	}
+	if changed {
+		uc.save(ctx, root, idx)
+	}
+
+	resp := &SearchResponse{Query: query, Reindexed: reindexed, Hits: idx.search(terms)}
+	resp.Total = len(resp.Hits)
+	if len(resp.Hits) > limit {
+		resp.Hits = resp.Hits[:limit]
+		resp.Truncated = true
+	}
+	return resp, nil
+}
+
+func (uc *SearchUseCase) indexPath(root string) string {
+	return filepath.Join(root, uc.config.System.CacheDir, searchIndexFile)
+}
+
+// load reads the persisted index, or starts an empty one if it is missing or stale.
+func (uc *SearchUseCase) load(root string) *searchIndex {
+	idx := &searchIndex{Version: searchIndexVersion, Docs: make(map[string]*searchDoc)}
+	data, err := uc.fs.ReadFile(uc.indexPath(root))
+	if err != nil {
+		return idx
+	}
+	var persisted searchIndex
+	if err := json.Unmarshal(data, &persisted); err != nil || persisted.Version != searchIndexVersion || persisted.Docs == nil {
+		return idx
+	}
+	return &persisted
+}
+
+func (uc *SearchUseCase) save(ctx context.Context, root string, idx *searchIndex) {
+	data, err := json.Marshal(idx)
+	if err == nil {
+		if err = uc.fs.MkdirAll(filepath.Dir(uc.indexPath(root))); err == nil {
+			err = uc.fs.WriteFile(uc.indexPath(root), data)
+		}
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "failed to persist search index", "path", uc.indexPath(root), "error", err)
+	}
+}
+
+// refresh re-indexes the artifacts whose modification time changed and drops the ones
+// that disappeared. It reports how many files were (re)indexed and whether idx changed.
+func (uc *SearchUseCase) refresh(ctx context.Context, root string, idx *searchIndex) (int, bool, error) {
+	modules, err := listTreeModules(uc.fs, root)
+	if err != nil {
+		return 0, false, err
+	}
+
+	reindexed, changed := 0, false
+	seen := make(map[string]bool)
+	for _, m := range modules {
+		if err := ctx.Err(); err != nil {
+			return 0, false, err
+		}
+		rel, _ := filepath.Rel(root, m.Path)
+		module := filepath.ToSlash(rel)
+
+		for _, name := range []string{"codespec.md", "codemodel.md"} {
+			path := filepath.Join(m.Path, name)
+			info, err := uc.fs.Stat(path)
+			if err != nil {
+				continue
+			}
+			file := filepath.ToSlash(filepath.Join(rel, name))
+			seen[file] = true
+			stamp := info.ModTime().UnixNano()
+			if doc, ok := idx.Docs[file]; ok && doc.Stamp == stamp {
+				continue
+			}
+
+			data, err := uc.fs.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			doc := &searchDoc{Module: module, Stamp: stamp}
+			if name == "codespec.md" {
+				if spec, err := parseCodeSpec(data); err == nil && spec != nil {
+					doc.Units = specUnits(spec)
+				}
+			} else if model, err := parseCodeModel(data); err == nil {
+				doc.Units = bodyUnits(SearchFieldAnnotation, model.Body)
+			}
+			idx.Docs[file] = doc
+			reindexed++
+			changed = true
+		}
+	}
+
+	for file := range idx.Docs {
+		if !seen[file] {
+			delete(idx.Docs, file)
+			changed = true
+		}
+	}
+	return reindexed, changed, nil
+}
+
+func specUnits(spec *domain.CodeSpec) []searchUnit {
+	meta := spec.MetaData
+	var units []searchUnit
+	add := func(field, section, text string) {
+		if text = strings.TrimSpace(text); text != "" {
+			units = append(units, newSearchUnit(field, section, text))
+		}
+	}
+	add(SearchFieldTitle, "", meta.Title)
+	add(SearchFieldSummary, "", meta.Summary)
+	for _, c := range meta.Capabilities {
+		add(SearchFieldCapability, "", c)
+	}
+	for _, r := range meta.Requirements {
+		add(SearchFieldRequirement, r.ID, r.Desc)
+	}
+	return append(units, bodyUnits(SearchFieldSpecBody, spec.Body)...)
+}
+
+// bodyUnits splits a markdown body at its headings, so a hit points at one section.
+func bodyUnits(field, body string) []searchUnit {
+	var units []searchUnit
+	section, text := "", []string{}
+	flush := func() {
+		if joined := strings.TrimSpace(strings.Join(text, "\n")); joined != "" {
+			units = append(units, newSearchUnit(field, section, joined))
+		}
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "#") {
+			flush()
+			section, text = strings.TrimSpace(strings.TrimLeft(trimmed, "#")), nil
+		}
+		text = append(text, line)
+	}
+	flush()
+	return units
+}
+
+func newSearchUnit(field, section, text string) searchUnit {
+	terms := make(map[string]int)
+	for _, t := range tokenize(text) {
+		terms[t]++
+	}
+	return searchUnit{Field: field, Section: section, Text: text, Terms: terms}
+}
+
+// tokenize lowercases text and splits it into words, dropping stopwords and single characters.
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := words[:0]
+	for _, w := range words {
+		if len(w) > 1 && !searchStopwords[w] {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+func (idx *searchIndex) build() {
+	idx.postings = make(map[string]map[unitRef]int)
+	idx.lengths = make(map[unitRef]int)
+	total := 0
+	for file, doc := range idx.Docs {
+		for i, u := range doc.Units {
+			ref := unitRef{file: file, unit: i}
+			for term, tf := range u.Terms {
+				if idx.postings[term] == nil {
+					idx.postings[term] = make(map[unitRef]int)
+				}
+				idx.postings[term][ref] = tf
+				idx.lengths[ref] += tf
+			}
+			total += idx.lengths[ref]
+		}
+	}
+	idx.avgLen = 1
+	if len(idx.lengths) > 0 && total > 0 {
+		idx.avgLen = float64(total) / float64(len(idx.lengths))
+	}
+}
+
+// search ranks units by BM25, weighted by field, and scaled by the share of query
+// terms they contain so that units matching every term come first.
+func (idx *searchIndex) search(terms []string) []SearchHit {
+	const k1, b = 1.2, 0.75
+	n := float64(len(idx.lengths))
+
+	scores := make(map[unitRef]float64)
+	matched := make(map[unitRef]int)
+	unique := make(map[string]bool)
+	for _, term := range terms {
+		if unique[term] {
+			continue
+		}
+		unique[term] = true
+		postings := idx.postings[term]
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for ref, tf := range postings {
+			norm := 1 - b + b*float64(idx.lengths[ref])/idx.avgLen
+			scores[ref] += idf * float64(tf) * (k1 + 1) / (float64(tf) + k1*norm)
+			matched[ref]++
+		}
+	}
+
+	hits := make([]SearchHit, 0, len(scores))
+	for ref, score := range scores {
+		doc := idx.Docs[ref.file]
+		u := doc.Units[ref.unit]
+		score *= searchFieldWeights[u.Field] * float64(matched[ref]) / float64(len(unique))
+		hits = append(hits, SearchHit{
+			Module:  doc.Module,
+			File:    ref.file,
+			Field:   u.Field,
+			Section: u.Section,
+			Score:   math.Round(score*1000) / 1000,
+			Snippet: snippet(u.Text, terms),
+		})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].File != hits[j].File {
+			return hits[i].File < hits[j].File
+		}
+		return hits[i].Section < hits[j].Section
+	})
+	return hits
+}
+
+// snippet returns the text around the first query term, on a single line.
+func snippet(text string, terms []string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	lower, offsets := lowerWithOffsets(text)
+	at := -1
+	for _, t := range terms {
+		if i := strings.Index(lower, t); i >= 0 && (at < 0 || offsets[i] < at) {
+			at = offsets[i]
+		}
+	}
+	if at < 0 || len(text) <= 2*snippetRadius {
+		if len(text) > 2*snippetRadius {
+			return strings.TrimSpace(text[:wordBoundary(text, 2*snippetRadius)]) + "..."
+		}
+		return text
+	}
+
+	start, end := 0, len(text)
+	if at > snippetRadius {
+		start = min(wordBoundary(text, at-snippetRadius), at)
+	}
+	if at+snippetRadius < len(text) {
+		end = wordBoundary(text, at+snippetRadius)
+	}
+	out := strings.TrimSpace(text[start:end])
+	if start > 0 {
+		out = "..." + out
+	}
+	if end < len(text) {
+		out += "..."
+	}
+	return out
+}
+
+// lowerWithOffsets lowercases text rune by rune, as tokenize does, and maps each byte
+// offset of the result back to the offset of its rune in text: the case of a rune can
+// take a different number of bytes than the rune itself (Ⱥ, İ).
+func lowerWithOffsets(text string) (string, []int) {
+	var b strings.Builder
+	b.Grow(len(text))
+	offsets := make([]int, 0, len(text)+1)
+	for i, r := range text {
+		n := b.Len()
+		b.WriteRune(unicode.ToLower(r))
+		for ; n < b.Len(); n++ {
+			offsets = append(offsets, i)
+		}
+	}
+	return b.String(), append(offsets, len(text))
+}
+
+// wordBoundary moves i forward to the next space (or the end of text), keeping words whole.
+func wordBoundary(text string, i int) int {
+	if i >= len(text) {
+		return len(text)
+	}
+	if j := strings.IndexByte(text[i:], ' '); j >= 0 {
+		return i + j
+	}
+	return len(text)
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Josepavese/asdp/engine/domain"
+	"github.com/Josepavese/asdp/engine/system"
+)
+
+func TestSnippetNonASCII(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		// Ⱥ lowercases to a longer ⱥ, İ to a shorter i
+		{"longer lowercase", strings.Repeat("ȺȺ ", 200) + "needle here"},
+		{"shorter lowercase", strings.Repeat("İİ ", 200) + "needle here"},
+		{"no space before the match", strings.Repeat("Ⱥ", 200) + "needle"},
+		{"match at the start", "needle " + strings.Repeat("ȺȺ ", 200)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := snippet(tt.text, []string{"needle"})
+			if !strings.Contains(got, "needle") {
+				t.Errorf("snippet misses the match: %q", got)
+			}
+		})
+	}
+}
+
+func TestWordBoundaryPastEnd(t *testing.T) {
+	if got := wordBoundary("abc", 10); got != 3 {
+		t.Errorf("wordBoundary past the end = %d, want 3", got)
+	}
+}
+
+func TestSearchNonASCIIBody(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "codetree.md"), "---\nasdp_version: 0.1.0\n---\n")
+	writeFile(t, filepath.Join(root, "codespec.md"),
+		"---\ntitle: Root\nsummary: Root module\n---\n## Context\n"+strings.Repeat("ȺȺ ", 300)+"needle\n")
+
+	uc := NewSearchUseCase(system.NewRealFileSystem(), *domain.DefaultConfig())
+	resp, err := uc.Execute(context.Background(), root, "needle", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Hits) == 0 {
+		t.Fatal("no hit for needle")
+	}
+	if !strings.Contains(resp.Hits[0].Snippet, "needle") {
+		t.Errorf("snippet misses the match: %q", resp.Hits[0].Snippet)
+	}
+}
+
+func TestSearchRanking(t *testing.T) {
+	unit := func(field, text string) *searchDoc {
+		return &searchDoc{Module: ".", Units: []searchUnit{newSearchUnit(field, "", text)}}
+	}
+	tests := []struct {
+		name  string
+		query string
+		docs  map[string]*searchDoc
+		want  []string // Files, best first
+	}{
+		{
+			name:  "title over body",
+			query: "cache",
+			docs: map[string]*searchDoc{
+				"body":  unit(SearchFieldSpecBody, "cache layer"),
+				"title": unit(SearchFieldTitle, "cache layer"),
+			},
+			want: []string{"title", "body"},
+		},
+		{
+			name:  "every term over one repeated term",
+			query: "token budget",
+			docs: map[string]*searchDoc{
+				"one":  unit(SearchFieldSpecBody, "token token token token"),
+				"both": unit(SearchFieldSpecBody, "token budget"),
+			},
+			want: []string{"both", "one"},
+		},
+		{
+			name:  "rare term over common term",
+			query: "parser inotify",
+			docs: map[string]*searchDoc{
+				"common": unit(SearchFieldSpecBody, "parser notes"),
+				"rare":   unit(SearchFieldSpecBody, "inotify notes"),
+				"other":  unit(SearchFieldSpecBody, "parser design"),
+			},
+			want: []string{"rare", "common", "other"},
+		},
+		{
+			name:  "short unit over long unit",
+			query: "watch",
+			docs: map[string]*searchDoc{
+				"long":  unit(SearchFieldSpecBody, "watch "+strings.Repeat("filler words ", 20)),
+				"short": unit(SearchFieldSpecBody, "watch mode"),
+			},
+			want: []string{"short", "long"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := &searchIndex{Docs: tt.docs}
+			idx.build()
+			var got []string
+			for _, hit := range idx.search(tokenize(tt.query)) {
+				got = append(got, hit.File)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ranked %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchReindexesChangedFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "codetree.md"), "---\nasdp_version: 0.1.0\ncomponents:\n  - {name: store, path: store, has_spec: true}\n---\n")
+	writeFile(t, filepath.Join(root, "codespec.md"), "---\ntitle: Root\n---\n")
+	spec := filepath.Join(root, "store", "codespec.md")
+	writeFile(t, spec, "---\ntitle: Store\nsummary: Keeps pages\n---\n")
+
+	ctx := context.Background()
+	config := *domain.DefaultConfig()
+	resp, err := NewSearchUseCase(system.NewRealFileSystem(), config).Execute(ctx, root, "pages", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Reindexed != 2 || len(resp.Hits) != 1 || resp.Hits[0].Module != "store" || resp.Hits[0].Field != SearchFieldSummary {
+		t.Fatalf("first search: %+v", resp)
+	}
+
+	// A new use case reads the persisted index and re-reads only the changed spec
+	writeFile(t, spec, "---\ntitle: Store\nsummary: Keeps records\n---\n")
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(spec, later, later); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = NewSearchUseCase(system.NewRealFileSystem(), config).Execute(ctx, root, "pages", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Reindexed != 1 || len(resp.Hits) != 0 {
+		t.Errorf("search after an edit: %+v", resp)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
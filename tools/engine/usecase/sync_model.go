@@ -1,8 +1,13 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/Josepavese/asdp/engine/domain"
@@ -14,6 +19,9 @@ type SyncModelUseCase struct {
 	parser domain.ASTParser
 	hasher domain.ContentHasher
 	config domain.ModelSyncConfig
+
+	listenersMu sync.Mutex
+	listeners   []func(modulePath string)
 }
 
 func NewSyncModelUseCase(fs domain.FileSystem, parser domain.ASTParser, hasher domain.ContentHasher, config domain.ModelSyncConfig) *SyncModelUseCase {
@@ -25,15 +33,36 @@ func NewSyncModelUseCase(fs domain.FileSystem, parser domain.ASTParser, hasher d
 	}
 }
 
+// OnModelWritten registers fn to be called with the module path every time a
+// codemodel.md is rewritten. Listeners run synchronously on the syncing goroutine.
+func (uc *SyncModelUseCase) OnModelWritten(fn func(modulePath string)) {
+	uc.listenersMu.Lock()
+	defer uc.listenersMu.Unlock()
+	uc.listeners = append(uc.listeners, fn)
+}
+
+func (uc *SyncModelUseCase) notifyModelWritten(modulePath string) {
+	uc.listenersMu.Lock()
+	listeners := append([]func(string){}, uc.listeners...)
+	uc.listenersMu.Unlock()
+
+	for _, fn := range listeners {
+		fn(modulePath)
+	}
+}
+
 type SyncResult struct {
 	Path         string `json:"path"`
 	SymbolsFound int    `json:"symbols_found"`
 	OldHash      string `json:"old_hash"`
 	NewHash      string `json:"new_hash"`
 	Status       string `json:"status"` // "updated", "unchanged"
+
+	Incremental bool     `json:"incremental,omitempty"` // Only the files that changed since the last sync were parsed
+	Reparsed    []string `json:"reparsed,omitempty"`    // Those files, when Incremental
 }
 
-func (uc *SyncModelUseCase) Execute(path string) (*SyncResult, error) {
+func (uc *SyncModelUseCase) Execute(ctx context.Context, path string) (*SyncResult, error) {
 	absPath, err := validateAndExpandPath(path)
 	if err != nil {
 		return nil, err
@@ -43,20 +72,13 @@ func (uc *SyncModelUseCase) Execute(path string) (*SyncResult, error) {
 	result := &SyncResult{Path: path}
 
 	// 1. Calculate current Hash
-	newHash, err := uc.hasher.HashDir(path)
+	newHash, manifest, err := uc.hasher.ManifestDir(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash dir: %w", err)
 	}
 	result.NewHash = newHash
 
-	// 2. Parse Code for Symbols
-	symbols, err := uc.parser.ParseDir(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse dir: %w", err)
-	}
-	result.SymbolsFound = len(symbols)
-
-	// 3. Read existing CodeModel (to preserve Body)
+	// 2. Read existing CodeModel (to preserve Body, and the symbols of unchanged files)
 	modelPath := filepath.Join(path, "codemodel.md")
 	var existingBody string
 	var existingMeta domain.CodeModelMeta
@@ -79,23 +101,30 @@ func (uc *SyncModelUseCase) Execute(path string) (*SyncResult, error) {
 		result.OldHash = "none"
 	}
 
+	// 3. Parse Code for Symbols
+	symbols, err := uc.parseSymbols(ctx, path, existingMeta, manifest, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dir: %w", err)
+	}
+	result.SymbolsFound = len(symbols)
+	if len(symbols) == 0 {
+		slog.WarnContext(ctx, "no symbols found in module", "path", path)
+	}
+
 	// 4. Check if update is needed
 	// strictly we update if hash changed OR symbols changed (maybe comments update?)
 	// For now, let's always update to ensure correctness.
 
 	// 5. Construct new Metadata
-	lastModified := time.Time{}
-	uc.fs.Walk(path, func(p string, isDir bool) error {
-		if isDir {
-			return nil
+	// last_modified dates the sources, not the sync: a touch or checkout that leaves
+	// the hash unchanged keeps it, so it can be compared with the codespec's
+	lastModified := existingMeta.Integrity.LastModified
+	if existingMeta.Integrity.SrcHash != newHash || lastModified.IsZero() {
+		lastModified, err = uc.newestSource(ctx, path)
+		if err != nil {
+			return nil, err
 		}
-		if info, err := uc.fs.Stat(p); err == nil {
-			if info.ModTime().After(lastModified) {
-				lastModified = info.ModTime()
-			}
-		}
-		return nil
-	})
+	}
 
 	newMeta := domain.CodeModelMeta{
 		ASDPVersion: domain.Version,
@@ -107,6 +136,12 @@ func (uc *SyncModelUseCase) Execute(path string) (*SyncResult, error) {
 		},
 		Symbols: symbols,
 	}
+	if uc.config.FileManifest {
+		newMeta.Integrity.Files = manifest
+		if fp, ok := uc.parser.(domain.FileParser); ok {
+			newMeta.Integrity.ParserVersion = fp.ParserVersion()
+		}
+	}
 
 	// 6. Write back to file
 	// Marshal Frontmatter
@@ -120,11 +155,155 @@ func (uc *SyncModelUseCase) Execute(path string) (*SyncResult, error) {
 	if err := uc.fs.WriteFile(modelPath, []byte(newContent)); err != nil {
 		return nil, fmt.Errorf("failed to write file: %w", err)
 	}
+	uc.notifyModelWritten(path)
 
 	result.Status = "updated"
 	if result.OldHash == result.NewHash {
 		result.Status = "refreshed_metadata" // Hash match but we overwrote structure anyway
 	}
+	slog.InfoContext(ctx, "codemodel synced", "path", path, "symbols", result.SymbolsFound, "status", result.Status)
 
 	return result, nil
 }
+
+// ExecuteAll re-syncs every module listed in the codetree.md at root that already has a codemodel.md.
+func (uc *SyncModelUseCase) ExecuteAll(ctx context.Context, root string) ([]*SyncResult, error) {
+	absPath, err := validateAndExpandPath(root)
+	if err != nil {
+		return nil, err
+	}
+	root = absPath
+
+	modules, err := listTreeModules(uc.fs, root)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []string
+	for _, m := range modules {
+		if m.Component.HasModel {
+			targets = append(targets, m.Path)
+		}
+	}
+
+	results := make([]*SyncResult, 0, len(targets))
+	for i, target := range targets {
+		res, err := uc.Execute(ctx, target)
+		if err != nil {
+			return results, fmt.Errorf("failed to sync %s: %w", target, err)
+		}
+		results = append(results, res)
+		domain.ReportProgress(ctx, i+1, len(targets), fmt.Sprintf("%d/%d modules synced", i+1, len(targets)))
+	}
+
+	return results, nil
+}
+
+// newestSource returns the modification time of the most recently modified file of the
+// module at dir, skipping ASDP artifacts, hidden entries and sub-modules.
+func (uc *SyncModelUseCase) newestSource(ctx context.Context, dir string) (time.Time, error) {
+	var latest time.Time
+	entries, err := uc.fs.ReadDir(dir)
+	if err != nil {
+		return latest, nil
+	}
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return latest, err
+		}
+		name := e.Name()
+		full := filepath.Join(dir, name)
+		switch {
+		case strings.HasPrefix(name, "."):
+			continue
+		case e.IsDir():
+			if isModuleDir(uc.fs, full) {
+				continue
+			}
+			sub, err := uc.newestSource(ctx, full)
+			if err != nil {
+				return latest, err
+			}
+			if sub.After(latest) {
+				latest = sub
+			}
+		case name == "codespec.md" || name == "codemodel.md" || name == "codetree.md":
+			continue
+		case e.ModTime().After(latest):
+			latest = e.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// parseSymbols parses the module at path. When the parser supports it and the previous
+// codemodel recorded a file manifest from the same parser version, only the files
+// whose hash changed are re-parsed and the symbols of the others are kept.
+func (uc *SyncModelUseCase) parseSymbols(ctx context.Context, path string, previous domain.CodeModelMeta, manifest map[string]string, result *SyncResult) ([]domain.Symbol, error) {
+	fp, ok := uc.parser.(domain.FileParser)
+	if !ok || !uc.config.FileManifest || !coversSymbols(previous.Integrity.Files, previous.Symbols) ||
+		fp.ParserVersion() == "" || previous.Integrity.ParserVersion != fp.ParserVersion() {
+		symbols, err := uc.parser.ParseDir(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		sortSymbolsByFile(symbols)
+		return symbols, nil
+	}
+
+	diff := diffManifests(previous.Integrity.Files, manifest)
+	dropped := make(map[string]bool)
+	for _, f := range diff.changed() {
+		dropped[f] = true
+	}
+	var symbols []domain.Symbol
+	for _, sym := range previous.Symbols {
+		if !dropped[filepath.ToSlash(sym.FilePath)] {
+			symbols = append(symbols, sym)
+		}
+	}
+
+	reparse := append(append([]string{}, diff.Added...), diff.Modified...)
+	sort.Strings(reparse)
+	if len(reparse) > 0 {
+		parsed, err := fp.ParseFiles(ctx, path, reparse)
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, parsed...)
+	}
+	sortSymbolsByFile(symbols)
+
+	result.Incremental = true
+	result.Reparsed = reparse
+	return symbols, nil
+}
+
+// coversSymbols reports whether every symbol comes from a file of the manifest, i.e.
+// whether the manifest can tell when each of them needs re-parsing.
+func coversSymbols(manifest map[string]string, symbols []domain.Symbol) bool {
+	if manifest == nil {
+		return false
+	}
+	for _, sym := range symbols {
+		if _, ok := manifest[filepath.ToSlash(sym.FilePath)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// sortSymbolsByFile orders symbols the way a directory walk visits their files, keeping
+// the order of the symbols within a file, so full and incremental syncs agree.
+func sortSymbolsByFile(symbols []domain.Symbol) {
+	sort.SliceStable(symbols, func(i, j int) bool {
+		a := strings.Split(filepath.ToSlash(symbols[i].FilePath), "/")
+		b := strings.Split(filepath.ToSlash(symbols[j].FilePath), "/")
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Josepavese/asdp/engine/domain"
+	"github.com/Josepavese/asdp/engine/system"
+)
+
+func newTestSync(config *domain.Config) *SyncModelUseCase {
+	return NewSyncModelUseCase(system.NewRealFileSystem(), system.NewGoASTParser(*config),
+		system.NewSHA256ContentHasher(config.Hasher), config.Sync.Model)
+}
+
+func readModel(t *testing.T, dir string) domain.CodeModelMeta {
+	t.Helper()
+	data, err := system.NewRealFileSystem().ReadFile(filepath.Join(dir, "codemodel.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, err := parseCodeModel(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model.MetaData
+}
+
+func TestIncrementalSyncMatchesFullSync(t *testing.T) {
+	config := domain.DefaultConfig()
+	config.Sync.Model.FileManifest = true
+	config.Parsing.Go.CallGraph = true
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/m\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(dir, "a.go"), "package m\n\nfunc A() { B() }\n")
+	writeFile(t, filepath.Join(dir, "b.go"), "package m\n\nfunc B() {}\n")
+	writeFile(t, filepath.Join(dir, "sub", "c.go"), "package sub\n\nfunc C() {}\n")
+
+	ctx := context.Background()
+	uc := newTestSync(config)
+	if _, err := uc.Execute(ctx, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only a.go changes: b.go keeps the callee
+	writeFile(t, filepath.Join(dir, "a.go"), "package m\n\nimport \"example.com/m/sub\"\n\n// A calls B.\nfunc A() { B(); A2(); sub.C() }\n\nfunc A2() {}\n")
+	result, err := uc.Execute(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Incremental || !reflect.DeepEqual(result.Reparsed, []string{"a.go"}) {
+		t.Fatalf("sync was not incremental over a.go: %+v", result)
+	}
+	incremental := readModel(t, dir).Symbols
+
+	config.Sync.Model.FileManifest = false
+	if _, err := newTestSync(config).Execute(ctx, dir); err != nil {
+		t.Fatal(err)
+	}
+	full := readModel(t, dir).Symbols
+
+	if !reflect.DeepEqual(incremental, full) {
+		t.Errorf("incremental sync:\n%+v\nfull sync:\n%+v", incremental, full)
+	}
+	for _, sym := range full {
+		if sym.Name == "A" && !reflect.DeepEqual(sym.Calls, []string{"A2", "B", "sub:C"}) {
+			t.Errorf("A calls %v, want [A2 B sub:C]", sym.Calls)
+		}
+	}
+}
+
+func TestIncrementalSyncOverAddedAndRemovedFiles(t *testing.T) {
+	config := domain.DefaultConfig()
+	config.Sync.Model.FileManifest = true
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.go"), "package m\n\nfunc A() {}\n")
+	writeFile(t, filepath.Join(dir, "b.go"), "package m\n\nfunc B() {}\n")
+	writeFile(t, filepath.Join(dir, "d.go"), "package m\n\nfunc D() {}\n")
+	ctx := context.Background()
+	uc := newTestSync(config)
+	if _, err := uc.Execute(ctx, dir); err != nil {
+		t.Fatal(err)
+	}
+	if files := readModel(t, dir).Integrity.Files; len(files) != 3 {
+		t.Fatalf("manifest = %v, want the three sources", files)
+	}
+
+	writeFile(t, filepath.Join(dir, "a.go"), "package m\n\nfunc A2() {}\n")
+	writeFile(t, filepath.Join(dir, "c.go"), "package m\n\nfunc C() {}\n")
+	if err := os.Remove(filepath.Join(dir, "b.go")); err != nil {
+		t.Fatal(err)
+	}
+	result, err := uc.Execute(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Incremental || !reflect.DeepEqual(result.Reparsed, []string{"a.go", "c.go"}) {
+		t.Fatalf("sync did not re-parse just a.go and c.go: %+v", result)
+	}
+	var names []string
+	for _, sym := range readModel(t, dir).Symbols {
+		names = append(names, sym.Name)
+	}
+	if want := []string{"A2", "C", "D"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("symbols %v, want %v", names, want)
+	}
+}
+
+func TestSyncWithoutManifest(t *testing.T) {
+	config := domain.DefaultConfig()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.go"), "package m\n\nfunc A() {}\n")
+	ctx := context.Background()
+	uc := newTestSync(config)
+	for i := 0; i < 2; i++ {
+		result, err := uc.Execute(ctx, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Incremental {
+			t.Errorf("sync %d was incremental without a manifest", i+1)
+		}
+	}
+	if files := readModel(t, dir).Integrity.Files; files != nil {
+		t.Errorf("manifest %v recorded by default", files)
+	}
+}
@@ -1,9 +1,8 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
-	"io/fs"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -24,7 +23,7 @@ func NewSyncTreeUseCase(fs domain.FileSystem, config domain.TreeSyncConfig) *Syn
 	}
 }
 
-func (uc *SyncTreeUseCase) Execute(path string) (*domain.CodeTree, error) {
+func (uc *SyncTreeUseCase) Execute(ctx context.Context, path string) (*domain.CodeTree, error) {
 	absPath, err := validateAndExpandPath(path)
 	if err != nil {
 		return nil, err
@@ -45,7 +44,8 @@ func (uc *SyncTreeUseCase) Execute(path string) (*domain.CodeTree, error) {
 	}
 
 	// 2. Build Component Tree (with exclusions)
-	rootComp, err := uc.buildComponent(path, path, existingExcludes)
+	visited := 0
+	rootComp, err := uc.buildComponent(ctx, path, path, existingExcludes, &visited)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build tree: %w", err)
 	}
@@ -78,7 +78,15 @@ func (uc *SyncTreeUseCase) Execute(path string) (*domain.CodeTree, error) {
 	return tree, nil
 }
 
-func (uc *SyncTreeUseCase) buildComponent(root string, currentPath string, excludes []string) (*domain.Component, error) {
+// buildComponent describes currentPath and its sub-modules. visited counts the
+// directories scanned so far across the whole recursion, for progress reporting.
+func (uc *SyncTreeUseCase) buildComponent(ctx context.Context, root string, currentPath string, excludes []string, visited *int) (*domain.Component, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	*visited++
+	domain.ReportProgress(ctx, *visited, 0, fmt.Sprintf("%d directories visited", *visited))
+
 	relPath, _ := filepath.Rel(root, currentPath)
 	if relPath == "." {
 		relPath = "./"
@@ -131,38 +139,30 @@ func (uc *SyncTreeUseCase) buildComponent(root string, currentPath string, exclu
 		latest = info.ModTime()
 	}
 
-	// Read children
-	var children []domain.Component
-
-	// Hack: I'll use `Walk` to find immediate children
-	err := uc.fs.Walk(currentPath, func(path string, isDir bool) error {
-		if path == currentPath {
-			return nil // Root of this walk
-		}
+	// Read children: one directory listing per level, its entries already carry their mtime
+	entries, err := uc.fs.ReadDir(currentPath)
+	if err != nil {
+		return nil, err
+	}
 
-		// Update latest mtime for ANY file found in this walk (to detect deep changes)
-		if info, err := uc.fs.Stat(path); err == nil {
-			if info.ModTime().After(latest) {
-				latest = info.ModTime()
-			}
+	var children []domain.Component
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
 
-		// We only want immediate children for this node.
-		rel, _ := filepath.Rel(currentPath, path)
-		if len(strings.Split(rel, string(os.PathSeparator))) > 1 {
-			if isDir {
-				return fs.SkipDir
-			}
-			return nil
+		// Update latest mtime for ANY entry of this directory
+		if entry.ModTime().After(latest) {
+			latest = entry.ModTime()
 		}
-
-		if !isDir {
-			return nil
+		if !entry.IsDir() {
+			continue
 		}
 
-		dirName := filepath.Base(path)
+		path := filepath.Join(currentPath, entry.Name())
+		dirName := entry.Name()
 		if uc.isIgnoredDir(dirName, excludes) {
-			return fs.SkipDir
+			continue
 		}
 
 		if uc.isShallowDir(dirName) {
@@ -174,22 +174,20 @@ func (uc *SyncTreeUseCase) buildComponent(root string, currentPath string, exclu
 				Description:  "External dependencies (not scanned)",
 				LastModified: latest, // Best effort
 			})
-			return fs.SkipDir
+			continue
 		}
 
 		// Recurse to build sub-component
-		childComp, err := uc.buildComponent(root, path, excludes)
+		childComp, err := uc.buildComponent(ctx, root, path, excludes, visited)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		children = append(children, *childComp)
-
-		return fs.SkipDir
-	})
+	}
 
 	comp.Children = children
 	comp.LastModified = latest
-	return comp, err
+	return comp, nil
 }
 
 func (uc *SyncTreeUseCase) isIgnoredDir(name string, excludes []string) bool {
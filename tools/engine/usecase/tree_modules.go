@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// treeModule is an ASDP module listed in a project's codetree.md.
+type treeModule struct {
+	Path      string // Absolute path of the module directory
+	Component domain.Component
+}
+
+// listTreeModules flattens the codetree.md at root into its ASDP modules, i.e. the
+// components that carry a codespec.md or codemodel.md. The root itself comes first.
+func listTreeModules(fs domain.FileSystem, root string) ([]treeModule, error) {
+	data, err := fs.ReadFile(filepath.Join(root, "codetree.md"))
+	if err != nil {
+		return nil, fmt.Errorf("codetree.md not found at %s. Run asdp_sync_codetree first", root)
+	}
+	tree, err := parseCodeTree(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse codetree.md: %w", err)
+	}
+
+	var modules []treeModule
+
+	rootComp := domain.Component{Name: filepath.Base(root), Path: "./"}
+	if _, err := fs.Stat(filepath.Join(root, "codespec.md")); err == nil {
+		rootComp.HasSpec = true
+	}
+	if _, err := fs.Stat(filepath.Join(root, "codemodel.md")); err == nil {
+		rootComp.HasModel = true
+	}
+	if rootComp.HasSpec || rootComp.HasModel {
+		modules = append(modules, treeModule{Path: root, Component: rootComp})
+	}
+
+	var collect func(comps []domain.Component)
+	collect = func(comps []domain.Component) {
+		for _, c := range comps {
+			if c.HasSpec || c.HasModel {
+				modules = append(modules, treeModule{Path: filepath.Join(root, c.Path), Component: c})
+			}
+			collect(c.Children)
+		}
+	}
+	collect(tree.MetaData.Components)
+
+	return modules, nil
+}
+
+// childModules returns the nearest module directories below dir, using the boundary
+// rules of the parsers: a directory holding a codespec.md or codemodel.md starts a new
+// module (and is not descended into), ignored and hidden directories are skipped, and
+// plain directories are searched through.
+func childModules(ctx context.Context, fs domain.FileSystem, config domain.Config, dir string) ([]string, error) {
+	entries, err := fs.ReadDir(dir)
+	if err != nil {
+		return nil, nil // Unreadable directory: no children
+	}
+
+	var modules []string
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !e.IsDir() || isIgnoredModuleDir(config, e.Name()) {
+			continue
+		}
+
+		sub := filepath.Join(dir, e.Name())
+		if isModuleDir(fs, sub) {
+			modules = append(modules, sub)
+			continue
+		}
+		nested, err := childModules(ctx, fs, config, sub)
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, nested...)
+	}
+	return modules, nil
+}
+
+func isModuleDir(fs domain.FileSystem, dir string) bool {
+	if _, err := fs.Stat(filepath.Join(dir, "codespec.md")); err == nil {
+		return true
+	}
+	_, err := fs.Stat(filepath.Join(dir, "codemodel.md"))
+	return err == nil
+}
+
+// isIgnoredModuleDir mirrors the directory filter of GoASTParser.ParseDir.
+func isIgnoredModuleDir(config domain.Config, name string) bool {
+	if config.Parsing.SkipHidden && strings.HasPrefix(name, ".") {
+		return true
+	}
+	lower := strings.ToLower(name)
+	for _, p := range config.IgnorePatterns {
+		if strings.Contains(lower, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/Josepavese/asdp/engine/domain"
 )
 
 func validateAndExpandPath(path string) (string, error) {
@@ -33,3 +35,12 @@ func validateAndExpandPath(path string) (string, error) {
 
 	return filepath.Clean(path), nil
 }
+
+// globalAssetsDir resolves the installed ASDP agent assets (rules, workflows, skills).
+func globalAssetsDir(config domain.Config) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, config.System.GlobalAssetsDir), nil
+}
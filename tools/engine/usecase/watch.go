@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// Watch modes, see domain.WatchConfig.
+const (
+	WatchModeSync   = "sync"
+	WatchModeNotify = "notify"
+)
+
+// WatchUseCase keeps codemodels fresh while the server runs: source changes are
+// debounced, mapped to their owning module, and the stale modules are either re-synced
+// or reported to the OnStale listeners.
+type WatchUseCase struct {
+	fs      domain.FileSystem
+	watcher domain.FileWatcher
+	hasher  domain.ContentHasher
+	syncUC  *SyncModelUseCase
+	config  domain.Config
+
+	freshness *FreshnessService
+
+	listenersMu sync.Mutex
+	listeners   []func(modulePath string, changed []string)
+}
+
+func NewWatchUseCase(fs domain.FileSystem, watcher domain.FileWatcher, hasher domain.ContentHasher, syncUC *SyncModelUseCase, config domain.Config) *WatchUseCase {
+	return &WatchUseCase{
+		fs:      fs,
+		watcher: watcher,
+		hasher:  hasher,
+		syncUC:  syncUC,
+		config:  config,
+
+		freshness: NewFreshnessService(fs, hasher, config),
+	}
+}
+
+// OnStale registers fn to be called, in notify mode, with each module whose codemodel
+// went stale and the changed files (absolute paths) that made it so.
+func (uc *WatchUseCase) OnStale(fn func(modulePath string, changed []string)) {
+	uc.listenersMu.Lock()
+	defer uc.listenersMu.Unlock()
+	uc.listeners = append(uc.listeners, fn)
+}
+
+func (uc *WatchUseCase) notifyStale(modulePath string, changed []string) {
+	uc.listenersMu.Lock()
+	listeners := append([]func(string, []string){}, uc.listeners...)
+	uc.listenersMu.Unlock()
+
+	for _, fn := range listeners {
+		fn(modulePath, changed)
+	}
+}
+
+// Run watches root until ctx is done.
+func (uc *WatchUseCase) Run(ctx context.Context, root string) error {
+	absPath, err := validateAndExpandPath(root)
+	if err != nil {
+		return err
+	}
+	root = absPath
+
+	batches := make(chan []string, 64)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- uc.watcher.Watch(ctx, root, func(name string) bool {
+			return isIgnoredModuleDir(uc.config, name)
+		}, func(paths []string) {
+			select {
+			case batches <- paths:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	debounce := uc.config.Sync.Watch.Debounce
+	if debounce <= 0 {
+		debounce = 500 * time.Millisecond
+	}
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+
+	pending := make(map[string]bool)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-watchErr:
+			return err
+		case paths := <-batches:
+			for _, p := range paths {
+				if uc.isSourceChange(root, p) {
+					pending[p] = true
+				}
+			}
+			if len(pending) > 0 {
+				timer.Reset(debounce) // Wait until the burst (a save, a checkout) is over
+			}
+		case <-timer.C:
+			changed := pending
+			pending = make(map[string]bool)
+			uc.handle(ctx, root, changed)
+		}
+	}
+}
+
+// isSourceChange filters out the ASDP artifacts (rewritten by the syncs themselves)
+// and anything in an ignored or hidden directory.
+func (uc *WatchUseCase) isSourceChange(root, path string) bool {
+	switch filepath.Base(path) {
+	case "codespec.md", "codemodel.md", "codetree.md":
+		return false
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	for _, part := range strings.Split(filepath.Dir(rel), string(filepath.Separator)) {
+		if part != "." && isIgnoredModuleDir(uc.config, part) {
+			return false
+		}
+	}
+	return true
+}
+
+func (uc *WatchUseCase) handle(ctx context.Context, root string, changed map[string]bool) {
+	byModule := make(map[string][]string)
+	for path := range changed {
+		if module, ok := uc.owningModule(root, path); ok {
+			byModule[module] = append(byModule[module], path)
+		}
+	}
+
+	modules := make([]string, 0, len(byModule))
+	for m := range byModule {
+		modules = append(modules, m)
+	}
+	sort.Strings(modules)
+
+	for _, module := range modules {
+		if ctx.Err() != nil {
+			return
+		}
+		files := byModule[module]
+		sort.Strings(files)
+		if !uc.isStale(ctx, module) {
+			continue // e.g. a file touched but not modified
+		}
+
+		if uc.config.Sync.Watch.Mode == WatchModeNotify {
+			uc.notifyStale(module, files)
+			continue
+		}
+		res, err := uc.syncUC.Execute(ctx, module)
+		if err != nil {
+			slog.WarnContext(ctx, "watch: failed to sync codemodel", "path", module, "error", err)
+			continue
+		}
+		slog.InfoContext(ctx, "watch: codemodel re-synced", "path", module, "changed", len(files), "symbols", res.SymbolsFound)
+	}
+}
+
+// owningModule applies the boundary rule: the nearest directory at or above path that
+// holds a codespec.md or codemodel.md. Only modules with a codemodel.md are kept fresh.
+// A directory, as reported after lost events, starts the search itself.
+func (uc *WatchUseCase) owningModule(root, path string) (string, bool) {
+	start := filepath.Dir(path)
+	if info, err := uc.fs.Stat(path); err == nil && info.IsDir() {
+		start = path
+	}
+	for dir := start; ; dir = filepath.Dir(dir) {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", false
+		}
+		if isModuleDir(uc.fs, dir) {
+			_, err := uc.fs.Stat(filepath.Join(dir, "codemodel.md"))
+			return dir, err == nil
+		}
+		if dir == root {
+			return "", false
+		}
+	}
+}
+
+func (uc *WatchUseCase) isStale(ctx context.Context, module string) bool {
+	data, err := uc.fs.ReadFile(filepath.Join(module, "codemodel.md"))
+	if err != nil {
+		return false
+	}
+	model, err := parseCodeModel(data)
+	if err != nil {
+		return true // Unreadable frontmatter: a sync rewrites it
+	}
+	freshness, err := uc.freshness.Check(ctx, module, nil, model)
+	return err == nil && freshness.Status == "stale"
+}
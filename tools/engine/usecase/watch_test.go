@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Josepavese/asdp/engine/domain"
+	"github.com/Josepavese/asdp/engine/system"
+)
+
+// TestWatchOverflowReportsLeafModules feeds handle the directories a watcher reports
+// after losing events: a leaf module changed meanwhile must still be found stale.
+func TestWatchOverflowReportsLeafModules(t *testing.T) {
+	config := domain.DefaultConfig()
+	config.Sync.Watch.Mode = WatchModeNotify
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.mod"), "module example.com/m\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(root, "root.go"), "package m\n")
+	writeFile(t, filepath.Join(root, "leaf", "codespec.md"), "---\ntitle: Leaf\n---\n")
+	writeFile(t, filepath.Join(root, "leaf", "leaf.go"), "package leaf\n")
+
+	fs := system.NewRealFileSystem()
+	hasher := system.NewSHA256ContentHasher(config.Hasher)
+	syncUC := NewSyncModelUseCase(fs, system.NewGoASTParser(*config), hasher, config.Sync.Model)
+	ctx := context.Background()
+	for _, module := range []string{root, filepath.Join(root, "leaf")} {
+		if _, err := syncUC.Execute(ctx, module); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(root, "leaf", "leaf.go"), "package leaf\n\nfunc F() {}\n")
+
+	uc := NewWatchUseCase(fs, nil, hasher, syncUC, *config)
+	var stale []string
+	uc.OnStale(func(module string, changed []string) {
+		stale = append(stale, module)
+	})
+	changed := make(map[string]bool)
+	for _, dir := range []string{root, filepath.Join(root, "leaf")} {
+		if uc.isSourceChange(root, dir) {
+			changed[dir] = true
+		}
+	}
+	uc.handle(ctx, root, changed)
+
+	if want := []string{filepath.Join(root, "leaf")}; !reflect.DeepEqual(stale, want) {
+		t.Errorf("stale modules = %v, want %v", stale, want)
+	}
+}
+
+func TestWatchPathsStartingWithTwoDots(t *testing.T) {
+	config := domain.DefaultConfig()
+	config.Parsing.SkipHidden = false
+	root := filepath.Join(t.TempDir(), "project")
+	writeFile(t, filepath.Join(root, "..data", "codemodel.md"), "---\nsymbols: []\n---\n")
+	writeFile(t, filepath.Join(root, "..data", "a.go"), "package data\n")
+	writeFile(t, filepath.Join(root, "..b.go"), "package m\n")
+
+	uc := NewWatchUseCase(system.NewRealFileSystem(), nil, nil, nil, *config)
+	for _, path := range []string{filepath.Join(root, "..data", "a.go"), filepath.Join(root, "..b.go")} {
+		if !uc.isSourceChange(root, path) {
+			t.Errorf("%s is not a source change", path)
+		}
+	}
+	if module, ok := uc.owningModule(root, filepath.Join(root, "..data", "a.go")); !ok || module != filepath.Join(root, "..data") {
+		t.Errorf("owning module = %q, %v; want %s", module, ok, filepath.Join(root, "..data"))
+	}
+
+	outside := filepath.Join(filepath.Dir(root), "other.go")
+	if uc.isSourceChange(root, outside) {
+		t.Errorf("%s outside the root is a source change", outside)
+	}
+	if module, ok := uc.owningModule(root, outside); ok {
+		t.Errorf("%s outside the root is owned by %s", outside, module)
+	}
+}
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/Josepavese/asdp/engine/domain"
 	"github.com/Josepavese/asdp/engine/system"
@@ -17,19 +21,53 @@ import (
 func main() {
 	// Simple CLI args for testing
 	queryPath := flag.String("query", "", "Path to query context for (e.g. ./tools/mcp-server)")
+	listenAddr := flag.String("listen", "", "Serve MCP over Streamable HTTP on this address (e.g. 127.0.0.1:7777) instead of stdio")
+	rootPath := flag.String("root", "", "Project root published through MCP resources (default: current directory)")
+	watch := flag.Bool("watch", false, "Watch the project root and re-sync stale codemodels (or only report them, with sync.watch.mode: notify)")
 	flag.Parse()
 
+	projectRoot := *rootPath
+	if projectRoot == "" {
+		projectRoot, _ = os.Getwd()
+	}
+	if abs, err := filepath.Abs(projectRoot); err == nil {
+		projectRoot = abs
+	}
+
 	// Load Configuration
-	cfg, err := system.LoadConfig("") // Load from default locations
+	cfg, err := system.LoadConfig(projectRoot) // Defaults, ~/.asdp/config.yaml, then <root>/.asdp.yaml
 	if err != nil {
 		log.Printf("Warning: Failed to load config, using defaults: %v", err)
 		cfg = domain.DefaultConfig()
 	}
 
+	// Logging: ~/.asdp/logs/asdp-mcp-server.log, plus notifications/message to MCP clients
+	logOpts := &slog.HandlerOptions{Level: system.ParseLogLevel(cfg.System.LogLevel)}
+	var logFile slog.Handler
+	if f, err := system.OpenLogFile(cfg.System, "asdp-mcp-server"); err == nil {
+		defer f.Close()
+		logFile = slog.NewJSONHandler(f, logOpts)
+	} else {
+		log.Printf("Warning: Failed to open log file, logging to stderr: %v", err)
+		logFile = slog.NewTextHandler(os.Stderr, logOpts)
+	}
+	slog.SetDefault(slog.New(mcp.NewLogHandler(logFile)))
+	// SetDefault also redirects the log package; keep its fatal errors on stderr
+	log.SetOutput(os.Stderr)
+	log.SetFlags(log.LstdFlags)
+
 	// Dependency Injection
-	fs := system.NewRealFileSystem()
+	// The project index lets repeat calls reuse artifacts and hashes of unchanged files
+	index := system.OpenProjectIndex(projectRoot, cfg.System)
+	defer saveIndex(index)
+	go func() {
+		for range time.Tick(indexSaveInterval) {
+			saveIndex(index)
+		}
+	}()
+	fs := system.NewCachedFileSystem(index)
 	configLoader := system.NewConfigurationLoader()
-	hasher := system.NewSHA256ContentHasher(cfg.Hasher)
+	hasher := system.NewIndexedContentHasher(cfg.Hasher, index)
 	parser := system.NewPolyglotParser(*cfg) // Switched to Polyglot
 
 	queryUC := usecase.NewQueryContextUseCase(fs, hasher, *cfg)
@@ -39,10 +77,17 @@ func main() {
 	syncTreeUC := usecase.NewSyncTreeUseCase(fs, cfg.Sync.Tree)
 	manageExclusionsUC := usecase.NewManageExclusionsUseCase(fs, syncTreeUC)
 	functionUC := usecase.NewGetFunctionInfoUseCase(fs, parser, hasher, *cfg)
+	artifactsUC := usecase.NewModuleArtifactsUseCase(fs)
+	assetsUC := usecase.NewAgentAssetsUseCase(fs, *cfg)
+	dependentsUC := usecase.NewDependentsUseCase(fs)
+	findSymbolUC := usecase.NewFindSymbolUseCase(fs)
+	callGraphUC := usecase.NewCallGraphUseCase(fs)
+	searchUC := usecase.NewSearchUseCase(fs, *cfg)
+	watchUC := usecase.NewWatchUseCase(fs, system.NewFSWatcher(cfg.Sync.Watch), hasher, syncUC, *cfg)
 
 	// Mode 1: Query CLI (Testing)
 	if *queryPath != "" {
-		resp, err := queryUC.Execute(*queryPath)
+		resp, err := queryUC.Execute(context.Background(), *queryPath)
 		if err != nil {
 			log.Fatalf("Error querying context: %v", err)
 		}
@@ -55,8 +100,35 @@ func main() {
 	initProjectUC := usecase.NewInitProjectUseCase(initAgentUC, syncTreeUC, scaffoldUC)
 	validateUC := check.NewValidateProjectUseCase(fs, parser, hasher, configLoader, cfg)
 
-	// Mode 2: MCP Server (Default)
+	mcpServer := mcp.NewServer(queryUC, syncUC, scaffoldUC, initAgentUC, syncTreeUC, manageExclusionsUC, initProjectUC, validateUC, functionUC, artifactsUC, assetsUC, dependentsUC, findSymbolUC, callGraphUC, searchUC, watchUC, *cfg, projectRoot)
+
+	if *watch {
+		go func() {
+			if err := watchUC.Run(context.Background(), projectRoot); err != nil {
+				slog.Error("watch mode stopped", "root", projectRoot, "error", err)
+			}
+		}()
+	}
+
+	// Mode 2: MCP Server over Streamable HTTP (shared by several clients)
+	if *listenAddr != "" {
+		fmt.Fprintf(os.Stderr, "ASDP MCP Server v%s listening on http://%s/mcp\n", domain.Version, *listenAddr)
+		if err := mcpServer.ListenAndServe(*listenAddr); err != nil {
+			log.Fatalf("HTTP server failed: %v", err)
+		}
+		return
+	}
+
+	// Mode 3: MCP Server over stdio (Default)
 	fmt.Fprintf(os.Stderr, "ASDP MCP Server v%s started.\n", domain.Version)
-	mcpServer := mcp.NewServer(queryUC, syncUC, scaffoldUC, initAgentUC, syncTreeUC, manageExclusionsUC, initProjectUC, validateUC, functionUC, *cfg)
 	mcpServer.Serve()
 }
+
+// indexSaveInterval bounds what a killed server loses of its project index.
+const indexSaveInterval = time.Minute
+
+func saveIndex(index *system.ProjectIndex) {
+	if err := index.Save(); err != nil {
+		slog.Warn("failed to persist project index", "error", err)
+	}
+}
@@ -0,0 +1,278 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Streamable HTTP transport: a single endpoint that accepts POSTed JSON-RPC
+// messages, an optional GET SSE stream for server-initiated messages, and
+// DELETE to end a session. Sessions are identified by the Mcp-Session-Id header.
+
+const (
+	httpEndpoint      = "/mcp"
+	sessionHeader     = "Mcp-Session-Id"
+	versionHeader     = "MCP-Protocol-Version" // Sent by 2025-06-18 clients on every request after initialize
+	maxHTTPBodyBytes  = 10 * 1024 * 1024
+	sseKeepAlive      = 30 * time.Second
+	sseStreamCapacity = 64
+)
+
+type httpTransport struct {
+	server *Server
+}
+
+// ListenAndServe exposes the server over Streamable HTTP on addr (e.g. "127.0.0.1:7777").
+// It blocks until the listener fails.
+func (s *Server) ListenAndServe(addr string) error {
+	t := &httpTransport{server: s}
+
+	mux := http.NewServeMux()
+	mux.Handle(httpEndpoint, t)
+	return http.ListenAndServe(addr, mux)
+}
+
+func (t *httpTransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Guard against DNS rebinding: browsers always send Origin, local agents usually don't.
+	if !isLocalOrigin(r.Header.Get("Origin")) {
+		http.Error(w, "Forbidden origin", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		t.handlePost(w, r)
+	case http.MethodGet:
+		t.handleStream(w, r)
+	case http.MethodDelete:
+		t.handleDelete(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (t *httpTransport) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxHTTPBodyBytes))
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	// A batch would need its responses gathered into one array; reject it in a way clients can read
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(t.server.encodeResponse(nil, nil, &RpcError{Code: -32600, Message: "Batch requests are not supported: send one JSON-RPC message per POST"}))
+		return
+	}
+
+	var probe struct {
+		Method string          `json:"method"`
+		ID     json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(body, &probe); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(t.server.encodeResponse(nil, nil, &RpcError{Code: -32700, Message: "Parse error"}))
+		return
+	}
+
+	if probe.Method == "initialize" {
+		t.initialize(w, r, body)
+		return
+	}
+
+	sess, status := t.lookup(r)
+	if sess == nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	if v := r.Header.Get(versionHeader); v != "" && !isSupportedProtocolVersion(v) {
+		http.Error(w, "Unsupported "+versionHeader+": "+v, http.StatusBadRequest)
+		return
+	}
+
+	// Tool calls may emit progress while they run, so they are answered on an SSE
+	// stream when the client supports it. Everything else gets a plain JSON body.
+	isRequest := len(probe.ID) > 0 && string(probe.ID) != "null"
+	if isRequest && accepts(r, "text/event-stream") && (probe.Method == "tools/call" || !accepts(r, "application/json")) {
+		t.streamResponse(w, r, sess, body)
+		return
+	}
+
+	resp := t.server.handleMessage(r.Context(), sess, body)
+	if resp == nil {
+		// Notifications and client responses carry no reply
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+// initialize opens a session. It is only kept, and its id only returned, once
+// a protocol version has been agreed.
+func (t *httpTransport) initialize(w http.ResponseWriter, r *http.Request, body []byte) {
+	sess := newSession(newSessionID())
+	t.server.addSession(sess)
+
+	resp := t.server.handleMessage(r.Context(), sess, body)
+	if sess.protocolVersion() == "" {
+		t.server.removeSession(sess.id)
+	} else {
+		w.Header().Set(sessionHeader, sess.id)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+// streamResponse answers a single request on a short-lived SSE stream. Notifications
+// emitted while it runs (e.g. progress) are sent on the same stream, before the response.
+func (t *httpTransport) streamResponse(w http.ResponseWriter, r *http.Request, sess *session, body []byte) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	var mu sync.Mutex
+	send := func(msg []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		writeEvent(w, msg)
+		flusher.Flush()
+	}
+
+	ctx := withNotifier(r.Context(), func(method string, params interface{}) {
+		if msg, err := encodeNotification(method, params); err == nil {
+			send(msg)
+		}
+	})
+	if resp := t.server.handleMessage(ctx, sess, body); resp != nil {
+		send(resp)
+	}
+}
+
+// handleStream opens the long-lived SSE stream used for server-initiated messages.
+func (t *httpTransport) handleStream(w http.ResponseWriter, r *http.Request) {
+	if !accepts(r, "text/event-stream") {
+		http.Error(w, "Client must accept text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+	sess, status := t.lookup(r)
+	if sess == nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// Slow readers lose messages rather than stalling the server. A second stream would
+	// silently take over the first one's messages, so it is refused instead.
+	stream := make(chan []byte, sseStreamCapacity)
+	attached := sess.attachStream(func(msg []byte) {
+		select {
+		case stream <- msg:
+		default:
+		}
+	})
+	if !attached {
+		http.Error(w, "A stream is already open for this session", http.StatusConflict)
+		return
+	}
+	defer sess.attach(nil)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg := <-stream:
+			writeEvent(w, msg)
+			flusher.Flush()
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func (t *httpTransport) handleDelete(w http.ResponseWriter, r *http.Request) {
+	sess, status := t.lookup(r)
+	if sess == nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	t.server.removeSession(sess.id)
+	sess.attach(nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// lookup resolves the request's session, returning the HTTP status to reply with on failure.
+func (t *httpTransport) lookup(r *http.Request) (*session, int) {
+	id := r.Header.Get(sessionHeader)
+	if id == "" {
+		return nil, http.StatusBadRequest
+	}
+
+	sess, ok := t.server.findSession(id)
+	if !ok {
+		return nil, http.StatusNotFound
+	}
+	return sess, 0
+}
+
+func writeEvent(w io.Writer, msg []byte) {
+	fmt.Fprintf(w, "event: message\ndata: %s\n\n", msg)
+}
+
+func accepts(r *http.Request, mediaType string) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		value := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if value == mediaType || value == "*/*" {
+			return true
+		}
+	}
+	return false
+}
+
+func isLocalOrigin(origin string) bool {
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	switch u.Hostname() {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+	return false
+}
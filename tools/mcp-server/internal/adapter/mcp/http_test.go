@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+func newTestHTTPServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	s := &Server{config: *domain.DefaultConfig(), sessions: make(map[string]*session)}
+	ts := httptest.NewServer(&httpTransport{server: s})
+	t.Cleanup(ts.Close)
+
+	init := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`
+	resp, err := http.Post(ts.URL, "application/json", strings.NewReader(init))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	id := resp.Header.Get(sessionHeader)
+	if id == "" {
+		t.Fatalf("initialize returned no %s (status %d)", sessionHeader, resp.StatusCode)
+	}
+	return ts, id
+}
+
+func openStream(ctx context.Context, t *testing.T, url, id string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(sessionHeader, id)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestHTTPSecondStreamConflicts(t *testing.T) {
+	ts, id := newTestHTTPServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := openStream(ctx, t, ts.URL, id)
+	defer first.Body.Close()
+	if first.StatusCode != http.StatusOK {
+		t.Fatalf("first stream: status %d", first.StatusCode)
+	}
+
+	second := openStream(context.Background(), t, ts.URL, id)
+	second.Body.Close()
+	if second.StatusCode != http.StatusConflict {
+		t.Errorf("second stream: status %d, want %d", second.StatusCode, http.StatusConflict)
+	}
+
+	// Once the first stream is gone, the session can open another
+	cancel()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		third := openStream(context.Background(), t, ts.URL, id)
+		third.Body.Close()
+		if third.StatusCode == http.StatusOK {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("stream after the first closed: status %d", third.StatusCode)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHTTPRejectsBatch(t *testing.T) {
+	ts, id := newTestHTTPServer(t)
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(` [{"jsonrpc":"2.0","id":2,"method":"tools/list"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(sessionHeader, id)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Error *RpcError `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusBadRequest || body.Error == nil || body.Error.Code != -32600 || !strings.Contains(body.Error.Message, "Batch") {
+		t.Errorf("batch POST: status %d, error %+v", resp.StatusCode, body.Error)
+	}
+}
@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+)
+
+// mcpLogLevels maps the MCP (RFC 5424) level names onto slog levels, lowest first.
+var mcpLogLevels = []struct {
+	name  string
+	level slog.Level
+}{
+	{"debug", slog.LevelDebug},
+	{"info", slog.LevelInfo},
+	{"notice", slog.LevelInfo + 2},
+	{"warning", slog.LevelWarn},
+	{"error", slog.LevelError},
+	{"critical", slog.LevelError + 4},
+	{"alert", slog.LevelError + 8},
+	{"emergency", slog.LevelError + 12},
+}
+
+// defaultSessionLogLevel applies until the client sends logging/setLevel.
+const defaultSessionLogLevel = slog.LevelWarn
+
+func parseMCPLogLevel(name string) (slog.Level, bool) {
+	for _, l := range mcpLogLevels {
+		if l.name == name {
+			return l.level, true
+		}
+	}
+	return 0, false
+}
+
+func mcpLogLevelName(level slog.Level) string {
+	name := mcpLogLevels[0].name
+	for _, l := range mcpLogLevels {
+		if level >= l.level {
+			name = l.name
+		}
+	}
+	return name
+}
+
+func (s *Server) handleSetLevel(sess *session, params json.RawMessage) (struct{}, *RpcError) {
+	var levelParams SetLevelParams
+	if err := json.Unmarshal(params, &levelParams); err != nil {
+		return struct{}{}, &RpcError{Code: -32602, Message: "Missing required argument: level"}
+	}
+	level, ok := parseMCPLogLevel(levelParams.Level)
+	if !ok {
+		return struct{}{}, &RpcError{Code: -32602, Message: "Unknown log level: " + levelParams.Level}
+	}
+	sess.setLogLevel(level)
+	return struct{}{}, nil
+}
+
+// LogHandler is the slog handler of the server process. Every record goes to the
+// log file; records logged with the context of an MCP request are also sent to that
+// request's client as notifications/message, filtered by its logging/setLevel.
+type LogHandler struct {
+	file   slog.Handler
+	attrs  []slog.Attr
+	prefix string // Dotted group path for attributes added after WithGroup
+}
+
+func NewLogHandler(file slog.Handler) *LogHandler {
+	return &LogHandler{file: file}
+}
+
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.file.Enabled(ctx, level) {
+		return true
+	}
+	sess, ok := sessionFrom(ctx)
+	return ok && level >= sess.logLevel()
+}
+
+func (h *LogHandler) Handle(ctx context.Context, r slog.Record) error {
+	var err error
+	if h.file.Enabled(ctx, r.Level) {
+		err = h.file.Handle(ctx, r)
+	}
+
+	sess, ok := sessionFrom(ctx)
+	if !ok || r.Level < sess.logLevel() {
+		return err
+	}
+
+	data := map[string]interface{}{"message": r.Message}
+	for _, a := range h.attrs {
+		data[a.Key] = attrValue(a.Value)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		data[h.prefix+a.Key] = attrValue(a.Value)
+		return true
+	})
+	sess.notifier(ctx)("notifications/message", LoggingMessageParams{
+		Level:  mcpLogLevelName(r.Level),
+		Logger: "asdp",
+		Data:   data,
+	})
+	return err
+}
+
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.file = h.file.WithAttrs(attrs)
+	clone.attrs = append([]slog.Attr{}, h.attrs...)
+	for _, a := range attrs {
+		clone.attrs = append(clone.attrs, slog.Attr{Key: h.prefix + a.Key, Value: a.Value})
+	}
+	return &clone
+}
+
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.file = h.file.WithGroup(name)
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// attrValue converts an attribute to something encoding/json renders faithfully.
+func attrValue(v slog.Value) interface{} {
+	v = v.Resolve()
+	if err, ok := v.Any().(error); ok {
+		return err.Error()
+	}
+	if v.Kind() == slog.KindGroup {
+		group := make(map[string]interface{})
+		for _, a := range v.Group() {
+			group[a.Key] = attrValue(a.Value)
+		}
+		return group
+	}
+	return v.Any()
+}
@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// outputSchemaOf derives a tool's outputSchema from the Go type its structuredContent
+// is encoded from, following encoding/json naming so the schema matches the payload.
+// Named structs are emitted once under $defs, which keeps recursive types such as
+// domain.Component finite.
+func outputSchemaOf(v interface{}) map[string]interface{} {
+	g := &schemaGen{defs: make(map[string]interface{})}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// The root must be an inline object: MCP requires outputSchema.type == "object"
+	root := g.structSchema(t)
+	if len(g.defs) > 0 {
+		root["$defs"] = g.defs
+	}
+	return root
+}
+
+type schemaGen struct {
+	defs map[string]interface{}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (g *schemaGen) typeSchema(t reflect.Type) map[string]interface{} {
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(g.typeSchema(t.Elem()))
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		// nil slices encode as null
+		return map[string]interface{}{"type": []string{"array", "null"}, "items": g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": []string{"object", "null"}, "additionalProperties": g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := t.Name()
+		if _, seen := g.defs[name]; !seen {
+			g.defs[name] = nil // Placeholder breaks recursion
+			g.defs[name] = g.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
+	}
+	return map[string]interface{}{} // interface{} and friends: anything goes
+}
+
+func (g *schemaGen) structSchema(t reflect.Type) map[string]interface{} {
+	props := make(map[string]interface{})
+	var required []string
+	g.collectFields(t, props, &required)
+
+	schema := map[string]interface{}{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (g *schemaGen) collectFields(t reflect.Type, props map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, omitEmpty, skip := jsonFieldName(f)
+		if skip {
+			continue
+		}
+
+		// Untagged embedded structs are flattened by encoding/json
+		if f.Anonymous && f.Tag.Get("json") == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.collectFields(ft, props, required)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		props[name] = g.typeSchema(f.Type)
+		if !omitEmpty {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func jsonFieldName(f reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = f.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
+
+func nullable(schema map[string]interface{}) map[string]interface{} {
+	if ref, ok := schema["$ref"]; ok {
+		return map[string]interface{}{"anyOf": []interface{}{map[string]interface{}{"$ref": ref}, map[string]interface{}{"type": "null"}}}
+	}
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+	}
+	return schema
+}
+
+// jsonResult returns v both as pretty-printed text, for clients that predate structured
+// output, and as structuredContent.
+func jsonResult(v interface{}, isError bool) *CallToolResult {
+	jsonBytes, _ := json.MarshalIndent(v, "", "  ")
+	return &CallToolResult{
+		Content:           []ToolContent{{Type: "text", Text: string(jsonBytes)}},
+		StructuredContent: v,
+		IsError:           isError,
+	}
+}
@@ -0,0 +1,29 @@
+package mcp
+
+import (
+	"time"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+// progressInterval bounds how often notifications/progress is sent for one request,
+// so a walk over thousands of directories doesn't flood the client.
+const progressInterval = 100 * time.Millisecond
+
+// progressReporter turns engine progress callbacks into notifications/progress for token.
+func progressReporter(notify notifyFunc, token interface{}) domain.ProgressFunc {
+	var last time.Time
+	return func(progress, total int, message string) {
+		final := total > 0 && progress >= total
+		if !final && time.Since(last) < progressInterval {
+			return
+		}
+		last = time.Now()
+		notify("notifications/progress", ProgressParams{
+			ProgressToken: token,
+			Progress:      progress,
+			Total:         total,
+			Message:       message,
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The workflows and skills shipped in core/agent are served as MCP prompts, so
+// clients that never load .agent/ rules can still follow the protocol.
+
+// promptSpec parameterizes a workflow. When InlineContext is set and a path is
+// given, the prompt embeds the current asdp_query_context result for that path.
+type promptSpec struct {
+	Arguments     []PromptArgument
+	InlineContext bool
+}
+
+var promptSpecs = map[string]promptSpec{
+	"asdp-spec-sync": {
+		Arguments: []PromptArgument{
+			{Name: "path", Description: "ABSOLUTE path of the module to synchronize (Spec -> Model -> Tree).", Required: true},
+		},
+		InlineContext: true,
+	},
+	"asdp-init": {
+		Arguments: []PromptArgument{
+			{Name: "path", Description: "ABSOLUTE repository root path."},
+			{Name: "code_path", Description: "ABSOLUTE path where the actual code starts, if already known."},
+		},
+	},
+}
+
+// defaultPromptSpec applies to every other workflow or skill.
+var defaultPromptSpec = promptSpec{
+	Arguments: []PromptArgument{
+		{Name: "path", Description: "ABSOLUTE path of the module the workflow applies to (optional)."},
+	},
+	InlineContext: true,
+}
+
+func specForPrompt(name string) promptSpec {
+	if spec, ok := promptSpecs[name]; ok {
+		return spec
+	}
+	return defaultPromptSpec
+}
+
+func (s *Server) handleListPrompts(ctx context.Context) (*ListPromptsResult, *RpcError) {
+	assets, err := s.assetsUC.List(ctx)
+	if err != nil {
+		// Assets not installed: advertise nothing rather than failing the client
+		return &ListPromptsResult{Prompts: []Prompt{}}, nil
+	}
+
+	result := &ListPromptsResult{Prompts: make([]Prompt, 0, len(assets))}
+	for _, asset := range assets {
+		result.Prompts = append(result.Prompts, Prompt{
+			Name:        asset.Name,
+			Description: fmt.Sprintf("[ASDP %s] %s", asset.Kind, asset.Description),
+			Arguments:   specForPrompt(asset.Name).Arguments,
+		})
+	}
+	return result, nil
+}
+
+func (s *Server) handleGetPrompt(ctx context.Context, params json.RawMessage) (*GetPromptResult, *RpcError) {
+	var getParams GetPromptParams
+	if err := json.Unmarshal(params, &getParams); err != nil || getParams.Name == "" {
+		return nil, &RpcError{Code: -32602, Message: "Missing required argument: name"}
+	}
+
+	asset, err := s.assetsUC.Get(ctx, getParams.Name)
+	if err != nil {
+		return nil, &RpcError{Code: -32602, Message: fmt.Sprintf("Unknown prompt: %s", getParams.Name)}
+	}
+
+	spec := specForPrompt(asset.Name)
+	var text strings.Builder
+	text.WriteString(asset.Body)
+
+	var given []string
+	for _, arg := range spec.Arguments {
+		value := getParams.Arguments[arg.Name]
+		if value == "" {
+			if arg.Required {
+				return nil, &RpcError{Code: -32602, Message: fmt.Sprintf("Missing required argument: %s", arg.Name)}
+			}
+			continue
+		}
+		given = append(given, fmt.Sprintf("- `%s`: %s", arg.Name, value))
+	}
+	if len(given) > 0 {
+		text.WriteString("\n\n## Parameters\n\n")
+		text.WriteString(strings.Join(given, "\n"))
+		text.WriteString("\n")
+	}
+
+	if path := getParams.Arguments["path"]; spec.InlineContext && path != "" {
+		resp, err := s.queryUC.Execute(ctx, path)
+		if err != nil {
+			return nil, &RpcError{Code: -32000, Message: err.Error()}
+		}
+		jsonBytes, _ := json.MarshalIndent(resp, "", "  ")
+		fmt.Fprintf(&text, "\n## Current ASDP Context\n\nResult of `asdp_query_context(path=%q)` when this prompt was requested:\n\n```json\n%s\n```\n", path, jsonBytes)
+	}
+
+	return &GetPromptResult{
+		Description: asset.Description,
+		Messages: []PromptMessage{
+			{Role: "user", Content: ToolContent{Type: "text", Text: text.String()}},
+		},
+	}, nil
+}
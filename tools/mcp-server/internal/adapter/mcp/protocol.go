@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// latestProtocolVersion is the newest MCP revision this server implements.
+// It is kept in sync with mcp_protocol_version in version.yaml by tools/cmd/version-manager.
+const latestProtocolVersion = "2025-06-18"
+
+// supportedProtocolVersions lists every revision the server can speak, newest first.
+var supportedProtocolVersions = []string{latestProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// Revisions that introduced features the server gates on.
+const (
+	versionStructuredOutput = "2025-06-18" // structuredContent and outputSchema
+	versionResourceLinks    = "2025-06-18" // resource_link content in tool results
+)
+
+var protocolVersionPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// protocolFeatures records what a session may use, given its negotiated version.
+type protocolFeatures struct {
+	StructuredOutput bool
+	ResourceLinks    bool
+}
+
+func featuresFor(version string) protocolFeatures {
+	// Versions are dates, so they order as strings
+	return protocolFeatures{
+		StructuredOutput: version >= versionStructuredOutput,
+		ResourceLinks:    version >= versionResourceLinks,
+	}
+}
+
+// UnsupportedVersionData is carried in the initialize error so clients can retry with a version we speak.
+type UnsupportedVersionData struct {
+	Requested string   `json:"requested"`
+	Supported []string `json:"supported"`
+}
+
+// negotiateProtocolVersion picks the version to answer initialize with. A supported
+// request is echoed back; a newer one gets the newest version we allow (the client
+// then decides whether to continue); one older than anything we speak is an error.
+// maxVersion, from MCPConfig.ProtocolVersion, caps the negotiation when set.
+func negotiateProtocolVersion(requested, maxVersion string) (string, *RpcError) {
+	offered := make([]string, 0, len(supportedProtocolVersions))
+	for _, v := range supportedProtocolVersions {
+		if maxVersion == "" || v <= maxVersion {
+			offered = append(offered, v)
+		}
+	}
+	if len(offered) == 0 {
+		// A misconfigured cap should not lock every client out
+		offered = supportedProtocolVersions
+	}
+
+	unsupported := func(reason string) *RpcError {
+		return &RpcError{
+			Code:    errInvalidParams,
+			Message: fmt.Sprintf("%s; this server supports MCP protocol versions %s", reason, strings.Join(offered, ", ")),
+			Data:    UnsupportedVersionData{Requested: requested, Supported: offered},
+		}
+	}
+
+	if requested == "" {
+		return "", unsupported("Missing protocolVersion in initialize")
+	}
+	if !protocolVersionPattern.MatchString(requested) {
+		return "", unsupported(fmt.Sprintf("Malformed protocol version %q (expected YYYY-MM-DD)", requested))
+	}
+
+	// offered is newest first: the first version not newer than the request wins
+	for _, v := range offered {
+		if v <= requested {
+			return v, nil
+		}
+	}
+	return "", unsupported(fmt.Sprintf("Unsupported protocol version %s", requested))
+}
+
+// downgradeResult drops the parts of a tool result the session's protocol version predates.
+func downgradeResult(result *CallToolResult, features protocolFeatures) {
+	if !features.StructuredOutput {
+		result.StructuredContent = nil
+	}
+	if !features.ResourceLinks {
+		content := result.Content[:0]
+		for _, c := range result.Content {
+			if c.Type != "resource_link" {
+				content = append(content, c)
+			}
+		}
+		result.Content = content
+	}
+}
+
+func isSupportedProtocolVersion(version string) bool {
+	for _, v := range supportedProtocolVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+)
+
+// ASDP artifacts are published as read-only JSON resources:
+//
+//	asdp://codetree                       the project hierarchy
+//	asdp://module/{path}/codespec         a module's intent (path is relative to the project root)
+//	asdp://module/{path}/codemodel        a module's structure
+//
+// The root module omits the path segment, e.g. asdp://module/codespec.
+
+const (
+	treeURI         = "asdp://codetree"
+	moduleURIPrefix = "asdp://module/"
+	jsonMimeType    = "application/json"
+
+	artifactSpec  = "codespec"
+	artifactModel = "codemodel"
+
+	// errResourceNotFound is the MCP error code for unknown resource URIs.
+	errResourceNotFound = -32002
+)
+
+func moduleURI(relPath, artifact string) string {
+	if relPath == "" {
+		return moduleURIPrefix + artifact
+	}
+	return moduleURIPrefix + relPath + "/" + artifact
+}
+
+// parseModuleURI resolves asdp://module/{path}/{artifact} to the module directory and artifact.
+func (s *Server) parseModuleURI(uri string) (string, string, error) {
+	if !strings.HasPrefix(uri, moduleURIPrefix) {
+		return "", "", fmt.Errorf("unsupported resource URI: %s", uri)
+	}
+	rest := strings.TrimPrefix(uri, moduleURIPrefix)
+
+	relPath, artifact := "", rest
+	if i := strings.LastIndex(rest, "/"); i >= 0 {
+		relPath, artifact = rest[:i], rest[i+1:]
+	}
+	if artifact != artifactSpec && artifact != artifactModel {
+		return "", "", fmt.Errorf("unknown artifact %q in %s (expected codespec or codemodel)", artifact, uri)
+	}
+
+	dir := filepath.Join(s.projectRoot, filepath.FromSlash(relPath))
+	if rel, err := filepath.Rel(s.projectRoot, dir); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", "", fmt.Errorf("resource %s is outside the project root", uri)
+	}
+	return dir, artifact, nil
+}
+
+func (s *Server) handleListResources(ctx context.Context) (*ListResourcesResult, *RpcError) {
+	result := &ListResourcesResult{Resources: []Resource{}}
+
+	modules, err := s.artifactsUC.ListModules(ctx, s.projectRoot)
+	if err != nil {
+		// No codetree yet: nothing to publish
+		return result, nil
+	}
+
+	result.Resources = append(result.Resources, Resource{
+		URI:         treeURI,
+		Name:        "codetree",
+		Description: "Project hierarchy of " + s.projectRoot,
+		MimeType:    jsonMimeType,
+	})
+
+	for _, m := range modules {
+		label := m.RelPath
+		if label == "" {
+			label = m.Name
+		}
+		if m.HasSpec {
+			result.Resources = append(result.Resources, Resource{
+				URI:         moduleURI(m.RelPath, artifactSpec),
+				Name:        label + " codespec",
+				Description: m.Description,
+				MimeType:    jsonMimeType,
+			})
+		}
+		if m.HasModel {
+			result.Resources = append(result.Resources, Resource{
+				URI:         moduleURI(m.RelPath, artifactModel),
+				Name:        label + " codemodel",
+				Description: "Symbols and integrity of " + label,
+				MimeType:    jsonMimeType,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Server) handleListResourceTemplates() (*ListResourceTemplatesResult, *RpcError) {
+	return &ListResourceTemplatesResult{
+		ResourceTemplates: []ResourceTemplate{
+			{
+				URITemplate: moduleURIPrefix + "{path}/" + artifactSpec,
+				Name:        "Module CodeSpec",
+				Description: "Parsed codespec.md (intent) of the module at {path}, relative to the project root.",
+				MimeType:    jsonMimeType,
+			},
+			{
+				URITemplate: moduleURIPrefix + "{path}/" + artifactModel,
+				Name:        "Module CodeModel",
+				Description: "Parsed codemodel.md (structure) of the module at {path}, relative to the project root.",
+				MimeType:    jsonMimeType,
+			},
+		},
+	}, nil
+}
+
+func (s *Server) handleReadResource(params json.RawMessage) (*ReadResourceResult, *RpcError) {
+	var readParams ReadResourceParams
+	if err := json.Unmarshal(params, &readParams); err != nil || readParams.URI == "" {
+		return nil, &RpcError{Code: -32602, Message: "Missing required argument: uri"}
+	}
+
+	var doc interface{}
+	var err error
+	if readParams.URI == treeURI {
+		doc, err = s.artifactsUC.Tree(s.projectRoot)
+	} else {
+		dir, artifact, parseErr := s.parseModuleURI(readParams.URI)
+		if parseErr != nil {
+			return nil, &RpcError{Code: errResourceNotFound, Message: parseErr.Error()}
+		}
+		if artifact == artifactSpec {
+			doc, err = s.artifactsUC.Spec(dir)
+		} else {
+			doc, err = s.artifactsUC.Model(dir)
+		}
+	}
+	if err != nil {
+		return nil, &RpcError{Code: errResourceNotFound, Message: err.Error()}
+	}
+
+	jsonBytes, _ := json.MarshalIndent(doc, "", "  ")
+	return &ReadResourceResult{
+		Contents: []ResourceContents{{URI: readParams.URI, MimeType: jsonMimeType, Text: string(jsonBytes)}},
+	}, nil
+}
+
+func (s *Server) handleSubscribe(sess *session, params json.RawMessage, on bool) (struct{}, *RpcError) {
+	var subParams SubscribeParams
+	if err := json.Unmarshal(params, &subParams); err != nil || subParams.URI == "" {
+		return struct{}{}, &RpcError{Code: -32602, Message: "Missing required argument: uri"}
+	}
+	sess.subscribe(subParams.URI, on)
+	return struct{}{}, nil
+}
+
+// moduleResourceURI maps an absolute module directory to its resource URI.
+// Modules outside the project root are not published.
+func (s *Server) moduleResourceURI(modulePath, artifact string) (string, bool) {
+	rel, err := filepath.Rel(s.projectRoot, modulePath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	if rel == "." {
+		rel = ""
+	}
+	return moduleURI(filepath.ToSlash(rel), artifact), true
+}
+
+// handleModelWritten tells subscribed sessions that a module's codemodel resource changed.
+func (s *Server) handleModelWritten(modulePath string) {
+	uri, ok := s.moduleResourceURI(modulePath, artifactModel)
+	if !ok {
+		return // Not published as a resource
+	}
+	for _, sess := range s.activeSessions() {
+		if sess.isSubscribed(uri) {
+			sess.notify("notifications/resources/updated", ResourceUpdatedParams{URI: uri})
+		}
+	}
+}
+
+// handleModuleStale reports a codemodel that no longer matches its sources (watch mode
+// "notify") as a warning, to every session logging at that level.
+func (s *Server) handleModuleStale(modulePath string, changed []string) {
+	slog.Warn("codemodel is stale", "path", modulePath, "changed", len(changed))
+	for _, sess := range s.activeSessions() {
+		if slog.LevelWarn < sess.logLevel() {
+			continue
+		}
+		sess.notify("notifications/message", LoggingMessageParams{
+			Level:  mcpLogLevelName(slog.LevelWarn),
+			Logger: "asdp",
+			Data: map[string]interface{}{
+				"message": "codemodel is stale, run asdp_sync_codemodel",
+				"path":    modulePath,
+				"changed": changed,
+			},
+		})
+	}
+}
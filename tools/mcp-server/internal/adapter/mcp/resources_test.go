@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"path"
+	"path/filepath"
+	"testing"
+)
+
+func TestModuleURIContainment(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "project")
+	s := &Server{projectRoot: root}
+
+	tests := []struct {
+		uri, dir string // dir is empty when the URI is rejected
+	}{
+		{"asdp://module/codespec", root},
+		{"asdp://module/store/codemodel", filepath.Join(root, "store")},
+		{"asdp://module/..data/codespec", filepath.Join(root, "..data")},
+		{"asdp://module/../codespec", ""},
+		{"asdp://module/../other/codespec", ""},
+		{"asdp://module/store/../../codespec", ""},
+	}
+	for _, tt := range tests {
+		dir, _, err := s.parseModuleURI(tt.uri)
+		if tt.dir == "" {
+			if err == nil {
+				t.Errorf("%s resolved to %s, want it rejected", tt.uri, dir)
+			}
+			continue
+		}
+		if err != nil || dir != tt.dir {
+			t.Errorf("%s resolved to %q, %v; want %q", tt.uri, dir, err, tt.dir)
+		}
+		if uri, ok := s.moduleResourceURI(dir, path.Base(tt.uri)); !ok || uri != tt.uri {
+			t.Errorf("module %s has URI %q, %v; want %s", dir, uri, ok, tt.uri)
+		}
+	}
+
+	if uri, ok := s.moduleResourceURI(filepath.Dir(root), artifactSpec); ok {
+		t.Errorf("module outside the root published as %s", uri)
+	}
+}
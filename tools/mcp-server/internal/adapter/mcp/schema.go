@@ -0,0 +1,248 @@
+package mcp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// errInvalidParams is the JSON-RPC error code for arguments that do not match a tool's input schema.
+const errInvalidParams = -32602
+
+// FieldError describes one argument that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// InvalidParamsData is carried in RpcError.Data so agents can fix every offending field at once.
+type InvalidParamsData struct {
+	Tool   string       `json:"tool"`
+	Errors []FieldError `json:"errors"`
+}
+
+// validateArguments checks args against the subset of JSON Schema used by tool definitions
+// (type, properties, required, enum, default, minLength, minimum, maximum,
+// additionalProperties, items) and returns a copy of args with defaults applied.
+func validateArguments(schema map[string]interface{}, args map[string]interface{}) (map[string]interface{}, []FieldError) {
+	out := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		out[k] = v
+	}
+	if schema == nil {
+		return out, nil
+	}
+
+	var errs []FieldError
+	validateObject(schema, out, "", &errs)
+	return out, errs
+}
+
+func validateObject(schema map[string]interface{}, obj map[string]interface{}, prefix string, errs *[]FieldError) {
+	props, _ := schema["properties"].(map[string]interface{})
+
+	for _, name := range stringList(schema["required"]) {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, FieldError{Field: prefix + name, Message: "is required"})
+		}
+	}
+
+	// Sorted so errors come back in a stable order
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propSchema, known := props[name].(map[string]interface{})
+		if !known {
+			if allowed, ok := schema["additionalProperties"].(bool); ok && !allowed {
+				*errs = append(*errs, FieldError{Field: prefix + name, Message: "is not a recognized argument"})
+			}
+			continue
+		}
+		validateValue(propSchema, obj[name], prefix+name, errs)
+	}
+
+	// Defaults are applied after validation so they never mask a missing required field
+	for name, p := range props {
+		propSchema, _ := p.(map[string]interface{})
+		if def, ok := propSchema["default"]; ok {
+			if _, present := obj[name]; !present {
+				// Registry and YAML defaults are Go ints: hand them over as JSON numbers
+				if f, ok := toFloat(def); ok {
+					def = f
+				}
+				obj[name] = def
+			}
+		}
+	}
+}
+
+func validateValue(schema map[string]interface{}, value interface{}, field string, errs *[]FieldError) {
+	if typ, ok := schema["type"].(string); ok && !matchesType(typ, value) {
+		*errs = append(*errs, FieldError{Field: field, Message: fmt.Sprintf("must be of type %s, got %s", typ, jsonTypeOf(value))})
+		return
+	}
+
+	if enum, ok := schema["enum"]; ok {
+		allowed := enumValues(enum)
+		found := false
+		for _, a := range allowed {
+			if a == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			*errs = append(*errs, FieldError{Field: field, Message: fmt.Sprintf("must be one of %s", formatEnum(allowed))})
+			return
+		}
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if min, ok := toFloat(schema["minimum"]); ok && v < min {
+			*errs = append(*errs, FieldError{Field: field, Message: fmt.Sprintf("must be >= %v", min)})
+		}
+		if max, ok := toFloat(schema["maximum"]); ok && v > max {
+			*errs = append(*errs, FieldError{Field: field, Message: fmt.Sprintf("must be <= %v", max)})
+		}
+	case string:
+		if min, ok := toInt(schema["minLength"]); ok && utf8.RuneCountInString(v) < min {
+			*errs = append(*errs, FieldError{Field: field, Message: fmt.Sprintf("must be at least %d characters", min)})
+		}
+	case map[string]interface{}:
+		validateObject(schema, v, field+".", errs)
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validateValue(items, item, fmt.Sprintf("%s[%d]", field, i), errs)
+			}
+		}
+	}
+}
+
+func matchesType(typ string, value interface{}) bool {
+	switch typ {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "null":
+		return value == nil
+	}
+	return true // Unknown types are not enforced
+}
+
+func jsonTypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// stringList accepts both the []string used by Go-declared schemas and the
+// []interface{} produced when a schema override is decoded from YAML.
+func stringList(v interface{}) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		out := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func enumValues(v interface{}) []interface{} {
+	switch list := v.(type) {
+	case []interface{}:
+		return list
+	case []string:
+		out := make([]interface{}, len(list))
+		for i, s := range list {
+			out[i] = s
+		}
+		return out
+	}
+	return nil
+}
+
+func formatEnum(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%v", v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
+	}
+	return 0, false
+}
+
+// invalidParamsError summarizes field errors in the message and lists them in Data.
+func invalidParamsError(toolName string, errs []FieldError) *RpcError {
+	parts := make([]string, len(errs))
+	for i, e := range errs {
+		parts[i] = e.Field + " " + e.Message
+	}
+	return &RpcError{
+		Code:    errInvalidParams,
+		Message: fmt.Sprintf("Invalid arguments for %s: %s", toolName, strings.Join(parts, "; ")),
+		Data:    InvalidParamsData{Tool: toolName, Errors: errs},
+	}
+}
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/Josepavese/asdp/engine/domain"
+)
+
+func TestValidateArguments(t *testing.T) {
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"path":  map[string]interface{}{"type": "string", "minLength": 1},
+			"mode":  map[string]interface{}{"type": "string", "enum": []string{"a", "b"}, "default": "a"},
+			"depth": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 10, "default": 1},
+			"tags":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		},
+		"required": []string{"path"},
+	}
+	tests := []struct {
+		name       string
+		args       map[string]interface{}
+		wantFields []string
+		wantArgs   map[string]interface{}
+	}{
+		{
+			name:     "defaults applied as JSON numbers",
+			args:     map[string]interface{}{"path": "/p"},
+			wantArgs: map[string]interface{}{"path": "/p", "mode": "a", "depth": float64(1)},
+		},
+		{
+			name:     "given values kept",
+			args:     map[string]interface{}{"path": "/p", "mode": "b", "depth": float64(3)},
+			wantArgs: map[string]interface{}{"path": "/p", "mode": "b", "depth": float64(3)},
+		},
+		{name: "missing required", args: map[string]interface{}{}, wantFields: []string{"path"}},
+		{name: "empty string", args: map[string]interface{}{"path": ""}, wantFields: []string{"path"}},
+		{name: "not in enum", args: map[string]interface{}{"path": "/p", "mode": "c"}, wantFields: []string{"mode"}},
+		{name: "not an integer", args: map[string]interface{}{"path": "/p", "depth": 1.5}, wantFields: []string{"depth"}},
+		{name: "out of range", args: map[string]interface{}{"path": "/p", "depth": float64(11)}, wantFields: []string{"depth"}},
+		{name: "wrong item type", args: map[string]interface{}{"path": "/p", "tags": []interface{}{"x", 1.0}}, wantFields: []string{"tags[1]"}},
+		{name: "unknown argument", args: map[string]interface{}{"path": "/p", "extra": true}, wantFields: []string{"extra"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errs := validateArguments(schema, tt.args)
+			var fields []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Fatalf("errors on %v, want %v (%v)", fields, tt.wantFields, errs)
+			}
+			if tt.wantArgs != nil && !reflect.DeepEqual(got, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", got, tt.wantArgs)
+			}
+		})
+	}
+}
+
+// TestCallToolAppliesNumericDefaults checks the value a handler receives for an omitted
+// argument, from the registry's default and from a config override.
+func TestCallToolAppliesNumericDefaults(t *testing.T) {
+	tests := []struct {
+		tool, arg string
+		override  map[string]interface{}
+		want      float64
+	}{
+		{tool: "asdp_search", arg: "limit", want: 20},
+		{tool: "asdp_call_graph", arg: "depth", want: 1},
+		{
+			tool: "asdp_find_symbol", arg: "limit", want: 7,
+			// YAML decodes a schema override's numbers as int
+			override: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"limit": map[string]interface{}{"type": "integer", "default": 7},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			s := &Server{config: *domain.DefaultConfig()}
+			if tt.override != nil {
+				s.config.MCP.ToolDefinitions = map[string]domain.ToolMetadata{tt.tool: {InputSchema: tt.override}}
+			}
+			var received map[string]interface{}
+			for _, registered := range s.registerTools() {
+				registered.Handler = func(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError) {
+					received = args
+					return &CallToolResult{}, nil
+				}
+				s.tools = append(s.tools, registered)
+			}
+
+			params, _ := json.Marshal(CallToolParams{Name: tt.tool, Arguments: map[string]interface{}{"path": "/p", "query": "q", "symbol": "S"}})
+			if _, rpcErr := s.handleCallTool(context.Background(), newSession("test"), params); rpcErr != nil {
+				t.Fatalf("call failed: %+v", rpcErr)
+			}
+			if got, ok := received[tt.arg].(float64); !ok || got != tt.want {
+				t.Errorf("%s arrived as %#v, want float64 %v", tt.arg, received[tt.arg], tt.want)
+			}
+		})
+	}
+}
@@ -2,10 +2,14 @@ package mcp
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"runtime/debug"
+	"sync"
 
 	"github.com/Josepavese/asdp/engine/domain"
 	"github.com/Josepavese/asdp/engine/usecase"
@@ -22,11 +26,23 @@ type Server struct {
 	initProjectUC      *usecase.InitProjectUseCase
 	validateUC         *check.ValidateProjectUseCase
 	functionUC         *usecase.GetFunctionInfoUseCase
+	artifactsUC        *usecase.ModuleArtifactsUseCase
+	assetsUC           *usecase.AgentAssetsUseCase
+	dependentsUC       *usecase.DependentsUseCase
+	findSymbolUC       *usecase.FindSymbolUseCase
+	callGraphUC        *usecase.CallGraphUseCase
+	searchUC           *usecase.SearchUseCase
+	watchUC            *usecase.WatchUseCase
 	config             domain.Config
+	projectRoot        string // Root whose codetree backs resources/list
+	tools              []tool // Registered tools, before config overrides
+
+	sessionsMu sync.Mutex
+	sessions   map[string]*session
 }
 
-func NewServer(queryUC *usecase.QueryContextUseCase, syncUC *usecase.SyncModelUseCase, scaffoldUC *usecase.ScaffoldUseCase, initAgentUC *usecase.InitAgentUseCase, syncTreeUC *usecase.SyncTreeUseCase, manageExclusionsUC *usecase.ManageExclusionsUseCase, initProjectUC *usecase.InitProjectUseCase, validateUC *check.ValidateProjectUseCase, functionUC *usecase.GetFunctionInfoUseCase, config domain.Config) *Server {
-	return &Server{
+func NewServer(queryUC *usecase.QueryContextUseCase, syncUC *usecase.SyncModelUseCase, scaffoldUC *usecase.ScaffoldUseCase, initAgentUC *usecase.InitAgentUseCase, syncTreeUC *usecase.SyncTreeUseCase, manageExclusionsUC *usecase.ManageExclusionsUseCase, initProjectUC *usecase.InitProjectUseCase, validateUC *check.ValidateProjectUseCase, functionUC *usecase.GetFunctionInfoUseCase, artifactsUC *usecase.ModuleArtifactsUseCase, assetsUC *usecase.AgentAssetsUseCase, dependentsUC *usecase.DependentsUseCase, findSymbolUC *usecase.FindSymbolUseCase, callGraphUC *usecase.CallGraphUseCase, searchUC *usecase.SearchUseCase, watchUC *usecase.WatchUseCase, config domain.Config, projectRoot string) *Server {
+	s := &Server{
 		queryUC:            queryUC,
 		syncUC:             syncUC,
 		scaffoldUC:         scaffoldUC,
@@ -36,8 +52,51 @@ func NewServer(queryUC *usecase.QueryContextUseCase, syncUC *usecase.SyncModelUs
 		initProjectUC:      initProjectUC,
 		validateUC:         validateUC,
 		functionUC:         functionUC,
+		artifactsUC:        artifactsUC,
+		assetsUC:           assetsUC,
+		dependentsUC:       dependentsUC,
+		findSymbolUC:       findSymbolUC,
+		callGraphUC:        callGraphUC,
+		searchUC:           searchUC,
+		watchUC:            watchUC,
 		config:             config,
+		projectRoot:        projectRoot,
+		sessions:           make(map[string]*session),
+	}
+	s.tools = s.registerTools()
+	syncUC.OnModelWritten(s.handleModelWritten)
+	watchUC.OnStale(s.handleModuleStale)
+	return s
+}
+
+func (s *Server) addSession(sess *session) {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	s.sessions[sess.id] = sess
+}
+
+func (s *Server) removeSession(id string) {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	delete(s.sessions, id)
+}
+
+func (s *Server) findSession(id string) (*session, bool) {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	sess, ok := s.sessions[id]
+	return sess, ok
+}
+
+// activeSessions returns a snapshot of every connected session, for broadcasts.
+func (s *Server) activeSessions() []*session {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	list := make([]*session, 0, len(s.sessions))
+	for _, sess := range s.sessions {
+		list = append(list, sess)
 	}
+	return list
 }
 
 // Serve starts the JSON-RPC loop on Stdin/Stdout
@@ -47,47 +106,126 @@ func (s *Server) Serve() {
 	buf := make([]byte, 1024*1024)
 	scanner.Buffer(buf, 10*1024*1024)
 
+	sess := newSession("stdio")
+	sess.attach(writeLine)
+	s.addSession(sess)
+	defer s.removeSession(sess.id)
+
+	// Requests run concurrently so a slow sync never blocks cheap lookups.
+	// Notifications (including cancellations) are handled inline to keep their ordering,
+	// and so is initialize, since it fixes the protocol version later requests depend on.
+	var inflight sync.WaitGroup
 	for scanner.Scan() {
-		line := scanner.Bytes()
-		s.handleMessage(line)
+		var req JsonRpcRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			// Not fatal (might be logs mixed in stdio), but worth recording
+			slog.WarnContext(withSession(context.Background(), sess), "ignoring unparsable JSON-RPC message", "bytes", len(scanner.Bytes()), "error", err)
+			continue
+		}
+		if req.ID == nil {
+			s.dispatch(context.Background(), sess, &req)
+			continue
+		}
+		if req.Method == "initialize" {
+			if resp := s.respond(context.Background(), sess, &req); resp != nil {
+				writeLine(resp)
+			}
+			continue
+		}
+
+		// Register before spawning so a cancellation on the next line always finds it
+		ctx, done := sess.begin(context.Background(), req.ID)
+		inflight.Add(1)
+		go func(req JsonRpcRequest) {
+			defer inflight.Done()
+			defer done()
+			if resp := s.respond(ctx, sess, &req); resp != nil {
+				writeLine(resp)
+			}
+		}(req)
 	}
 
 	if err := scanner.Err(); err != nil {
 		log.Printf("Scanner error: %v", err)
 	}
+	inflight.Wait()
+}
+
+var stdoutMu sync.Mutex
+
+// writeLine serializes every outgoing stdio message so concurrent responses never interleave.
+func writeLine(msg []byte) {
+	stdoutMu.Lock()
+	defer stdoutMu.Unlock()
+	fmt.Printf("%s\n", string(msg))
 }
 
-func (s *Server) handleMessage(data []byte) {
+// handleMessage dispatches a single JSON-RPC message synchronously and returns the
+// encoded response, or nil for notifications, cancelled requests and unparsable input.
+func (s *Server) handleMessage(ctx context.Context, sess *session, data []byte) []byte {
 	var req JsonRpcRequest
 	if err := json.Unmarshal(data, &req); err != nil {
-		// Ignore parse errors (might be logs mixed in stdio)
-		return
+		slog.WarnContext(withSession(ctx, sess), "ignoring unparsable JSON-RPC message", "bytes", len(data), "error", err)
+		return nil
+	}
+	if req.ID == nil {
+		s.dispatch(ctx, sess, &req)
+		return nil
 	}
 
-	var resp interface{}
-	var err *RpcError
+	ctx, done := sess.begin(ctx, req.ID)
+	defer done()
+	return s.respond(ctx, sess, &req)
+}
+
+// respond runs a request and encodes its response.
+func (s *Server) respond(ctx context.Context, sess *session, req *JsonRpcRequest) []byte {
+	resp, err := s.dispatch(ctx, sess, req)
+	if ctx.Err() != nil {
+		// The client cancelled (or went away): it no longer expects a response
+		return nil
+	}
+	return s.encodeResponse(req.ID, resp, err)
+}
 
+func (s *Server) dispatch(ctx context.Context, sess *session, req *JsonRpcRequest) (interface{}, *RpcError) {
+	ctx = withSession(ctx, sess)
 	switch req.Method {
 	case "initialize":
-		resp, err = s.handleInitialize(req.Params)
+		return s.handleInitialize(sess, req.Params)
 	case "tools/list":
-		resp, err = s.handleListTools()
+		return s.handleListTools(sess)
 	case "tools/call":
-		resp, err = s.handleCallTool(req.Params)
+		return s.handleCallTool(ctx, sess, req.Params)
+	case "resources/list":
+		return s.handleListResources(ctx)
+	case "resources/templates/list":
+		return s.handleListResourceTemplates()
+	case "resources/read":
+		return s.handleReadResource(req.Params)
+	case "resources/subscribe":
+		return s.handleSubscribe(sess, req.Params, true)
+	case "resources/unsubscribe":
+		return s.handleSubscribe(sess, req.Params, false)
+	case "prompts/list":
+		return s.handleListPrompts(ctx)
+	case "prompts/get":
+		return s.handleGetPrompt(ctx, req.Params)
+	case "logging/setLevel":
+		return s.handleSetLevel(sess, req.Params)
+	case "notifications/cancelled":
+		s.handleCancelled(sess, req.Params)
+		return nil, nil
 	default:
 		// Unknown method, but for notifications we shouldn't error.
-		// For now, we only care about request/response cycle.
 		if req.ID != nil {
-			err = &RpcError{Code: -32601, Message: "Method not found"}
+			return nil, &RpcError{Code: -32601, Message: "Method not found"}
 		}
-	}
-
-	if req.ID != nil {
-		s.sendResponse(req.ID, resp, err)
+		return nil, nil
 	}
 }
 
-func (s *Server) sendResponse(id interface{}, result interface{}, rpcErr *RpcError) {
+func (s *Server) encodeResponse(id interface{}, result interface{}, rpcErr *RpcError) []byte {
 	resp := JsonRpcResponse{
 		JSONRPC: "2.0",
 		ID:      id,
@@ -99,22 +237,30 @@ func (s *Server) sendResponse(id interface{}, result interface{}, rpcErr *RpcErr
 	}
 
 	bytes, _ := json.Marshal(resp)
-	fmt.Printf("%s\n", string(bytes))
+	return bytes
 }
 
 // --- Handlers ---
 
-func (s *Server) handleInitialize(params json.RawMessage) (*InitializeResult, *RpcError) {
+func (s *Server) handleInitialize(sess *session, params json.RawMessage) (*InitializeResult, *RpcError) {
+	var initParams InitializeParams
+	if err := json.Unmarshal(params, &initParams); err != nil {
+		return nil, &RpcError{Code: -32602, Message: "Invalid initialize params"}
+	}
+
+	version, rpcErr := negotiateProtocolVersion(initParams.ProtocolVersion, s.config.MCP.ProtocolVersion)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	sess.setProtocol(version, featuresFor(version))
+
 	return &InitializeResult{
-		ProtocolVersion: "2024-11-05", // MCP Version
-		Capabilities: struct {
-			Tools *struct {
-				ListChanged bool `json:"listChanged"`
-			} `json:"tools,omitempty"`
-		}{
-			Tools: &struct {
-				ListChanged bool `json:"listChanged"`
-			}{ListChanged: false},
+		ProtocolVersion: version,
+		Capabilities: ServerCapabilities{
+			Tools:     &ListChangedCapability{ListChanged: false},
+			Resources: &ResourcesCapability{Subscribe: true, ListChanged: false},
+			Prompts:   &ListChangedCapability{ListChanged: false},
+			Logging:   &struct{}{},
 		},
 		ServerInfo: struct {
 			Name    string `json:"name"`
@@ -126,321 +272,63 @@ func (s *Server) handleInitialize(params json.RawMessage) (*InitializeResult, *R
 	}, nil
 }
 
-func (s *Server) handleListTools() (*ListToolsResult, *RpcError) {
-	return &ListToolsResult{
-		Tools: []ToolDefinition{
-			{
-				Name:        "asdp_query_context",
-				Description: "Retrieve the ASDP context (Spec, Model, Freshness) for a given absolute path. Result: Returns a JSON object containing the merged CodeSpec (intent), CodeModel (structure), and current freshness status, allowing an agent to quickly understand a module's contract and implementation.",
-				InputSchema: map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"path": map[string]interface{}{
-							"type":        "string",
-							"description": "ABSOLUTE path to the module (e.g. /home/user/project/module)",
-						},
-					},
-					"required": []string{"path"},
-				},
-			},
-			{
-				Name:        "asdp_sync_codemodel",
-				Description: "Automatically scans the source code and updates the codemodel.md file. Result: Returns a SyncResult JSON with the count of symbols identified (functions, structs, interfaces including start/end lines) and the integrity hash of the source files.",
-				InputSchema: map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"path": map[string]interface{}{
-							"type":        "string",
-							"description": "ABSOLUTE path to the module (e.g. /home/user/project/module)",
-						},
-					},
-					"required": []string{"path"},
-				},
-			},
-			{
-				Name:        "asdp_sync_codetree",
-				Description: "Automatically scans the project directory and updates the codetree.md file. Result: Returns a JSON representation of the project hierarchy, listing all modules and their ASDP compliance status (presence of codespec/codemodel).",
-				InputSchema: map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"path": map[string]interface{}{
-							"type":        "string",
-							"description": "ABSOLUTE path to the project root or sub-directory.",
-						},
-					},
-					"required": []string{"path"},
-				},
-			},
-			{
-				Name:        "asdp_scaffold",
-				Description: "Create a new ASDP-compliant module or backfill missing files (codespec/codemodel) in an existing one. Safe to run on existing directories; will not overwrite existing files. Result: Returns a success message.",
-				InputSchema: map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"name": map[string]interface{}{
-							"type":        "string",
-							"description": "Module name. Use '.' to scaffold directly in the provided path.",
-						},
-						"type": map[string]interface{}{
-							"type":        "string",
-							"description": "Module type (library, service, app). Default: library",
-						},
-						"path": map[string]interface{}{
-							"type":        "string",
-							"description": "ABSOLUTE parent directory (or target directory if name='.').",
-						},
-						"title": map[string]interface{}{
-							"type":        "string",
-							"description": "Title of the module (e.g. 'User Authentication Service'). Required.",
-						},
-						"summary": map[string]interface{}{
-							"type":        "string",
-							"description": "Brief summary of the module's purpose. Required.",
-						},
-						"context": map[string]interface{}{
-							"type":        "string",
-							"description": "Detailed context and reasoning for this module. Required.",
-						},
-					},
-					"required": []string{"name", "path", "title", "summary", "context"},
-				},
-			},
-			{
-				Name:        "asdp_init_agent",
-				Description: "Copies ASDP Agent assets into the local project. Result: Returns a list of files copied into the .agent/ directory (Rules, Workflows, etc.) to enable ASDP-native agent behavior.",
-				InputSchema: map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"path": map[string]interface{}{
-							"type":        "string",
-							"description": "ABSOLUTE project root path (e.g. /home/user/project)",
-						},
-					},
-				},
-			},
-			{
-				Name:        "asdp_init_project",
-				Description: "Unified initialization of an ASDP project. Result: Sets up .agent/ at project path AND anchors the CodeTree at a specified code path, ensuring the AI starts from the real code.",
-				InputSchema: map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"path": map[string]interface{}{
-							"type":        "string",
-							"description": "ABSOLUTE project repository root path.",
-						},
-						"code_path": map[string]interface{}{
-							"type":        "string",
-							"description": "ABSOLUTE path to where the actual code starts (e.g. /repo/tools).",
-						},
-					},
-					"required": []string{"path", "code_path"},
-				},
-			},
-			{
-				Name:        "asdp_validate",
-				Description: "Audit the ASDP project state. Returns a report of Errors (invalid state, integration blocking) and Warnings (staleness). Checks for mandatory files, strict content compliance, and synchronization freshness.",
-				InputSchema: map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"path": map[string]interface{}{
-							"type":        "string",
-							"description": "ABSOLUTE path to the project root.",
-						},
-					},
-					"required": []string{"path"},
-				},
-			},
-			{
-				Name:        "asdp_manage_exclusions",
-				Description: "This is a tool from the asdp MCP server.\nExclude specific folders or branches from the ASDP protocol to hide them from context scanning. This modifies codetree.md.",
-				InputSchema: map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"path": map[string]interface{}{
-							"type":        "string",
-							"description": "ABSOLUTE project root path.",
-						},
-						"target": map[string]interface{}{
-							"type":        "string",
-							"description": "Name of the folder or relative path to exclude (e.g. 'dist', 'legacy', 'temp/build').",
-						},
-						"action": map[string]interface{}{
-							"type":        "string",
-							"description": "Action to perform: 'add' or 'remove'.",
-							"default":     "add",
-						},
-					},
-					"required": []string{"path", "target", "action"},
-				},
-			},
-		},
-	}, nil
+func (s *Server) handleListTools(sess *session) (*ListToolsResult, *RpcError) {
+	structured := sess.protocolFeatures().StructuredOutput
+	result := &ListToolsResult{Tools: []ToolDefinition{}}
+	for _, t := range s.activeTools() {
+		def := ToolDefinition{
+			Name:        t.Name,
+			Description: t.Metadata.Description,
+			InputSchema: t.Metadata.InputSchema,
+		}
+		if structured && t.OutputSchema != nil {
+			def.OutputSchema = t.OutputSchema
+		}
+		result.Tools = append(result.Tools, def)
+	}
+	return result, nil
+}
+
+func (s *Server) handleCancelled(sess *session, params json.RawMessage) {
+	var cancelParams CancelledParams
+	if err := json.Unmarshal(params, &cancelParams); err != nil || cancelParams.RequestID == nil {
+		return
+	}
+	sess.cancel(cancelParams.RequestID)
 }
 
-func (s *Server) handleCallTool(params json.RawMessage) (*CallToolResult, *RpcError) {
+func (s *Server) handleCallTool(ctx context.Context, sess *session, params json.RawMessage) (*CallToolResult, *RpcError) {
 	var callParams CallToolParams
 	if err := json.Unmarshal(params, &callParams); err != nil {
 		return nil, &RpcError{Code: -32700, Message: "Parse error"}
 	}
+	if callParams.Meta != nil && callParams.Meta.ProgressToken != nil {
+		ctx = domain.WithProgress(ctx, progressReporter(sess.notifier(ctx), callParams.Meta.ProgressToken))
+	}
 
-	switch callParams.Name {
-	case "asdp_query_context":
-		path, _ := callParams.Arguments["path"].(string)
-		ctx, err := s.queryUC.Execute(path)
-		if err != nil {
-			return nil, &RpcError{Code: -32000, Message: err.Error()}
-		}
-		jsonBytes, _ := json.MarshalIndent(ctx, "", "  ")
-		return &CallToolResult{
-			Content: []ToolContent{{Type: "text", Text: string(jsonBytes)}},
-			IsError: ctx.Validation != nil && !ctx.Validation.IsValid,
-		}, nil
-
-	case "asdp_sync_codemodel":
-		path, _ := callParams.Arguments["path"].(string)
-		res, err := s.syncUC.Execute(path)
-		if err != nil {
-			return nil, &RpcError{Code: -32000, Message: err.Error()}
-		}
-		jsonBytes, _ := json.MarshalIndent(res, "", "  ")
-		return &CallToolResult{
-			Content: []ToolContent{{Type: "text", Text: string(jsonBytes)}},
-		}, nil
-
-	case "asdp_sync_codetree":
-		path, _ := callParams.Arguments["path"].(string)
-		res, err := s.syncTreeUC.Execute(path)
-		if err != nil {
-			return nil, &RpcError{Code: -32000, Message: err.Error()}
-		}
-
-		// Recursive check for validation errors anywhere in the tree
-		hasAnyError := false
-		var checkErrors func(c []domain.Component)
-		checkErrors = func(comps []domain.Component) {
-			for _, c := range comps {
-				if !c.IsValid {
-					hasAnyError = true
-					return
-				}
-				checkErrors(c.Children)
-			}
-		}
-		if !res.MetaData.Root || res.MetaData.Components == nil {
-			// This part is tricky because the root itself might have a spec (not yet in TreeMeta)
-			// But SyncTree builds children.
-		}
-		checkErrors(res.MetaData.Components)
-
-		jsonBytes, _ := json.MarshalIndent(res, "", "  ")
-		return &CallToolResult{
-			Content: []ToolContent{{Type: "text", Text: string(jsonBytes)}},
-			IsError: hasAnyError,
-		}, nil
-
-	case "asdp_scaffold":
-		name, _ := callParams.Arguments["name"].(string)
-		modType, _ := callParams.Arguments["type"].(string)
-		path, _ := callParams.Arguments["path"].(string)
-		title, _ := callParams.Arguments["title"].(string)
-		summary, _ := callParams.Arguments["summary"].(string)
-		context, _ := callParams.Arguments["context"].(string)
-		if name == "" {
-			return nil, &RpcError{Code: -32602, Message: "Missing required argument: name"}
-		}
-		if modType == "" {
-			modType = "library"
-		}
-		resultMsg, err := s.scaffoldUC.Execute(usecase.ScaffoldParams{
-			Name:    name,
-			Type:    modType,
-			Path:    path,
-			Title:   title,
-			Summary: summary,
-			Context: context,
-		})
-		if err != nil {
-			return nil, &RpcError{Code: -32000, Message: err.Error()}
-		}
-		return &CallToolResult{Content: []ToolContent{{Type: "text", Text: resultMsg}}}, nil
-
-	case "asdp_init_agent":
-		path, _ := callParams.Arguments["path"].(string)
-		resultMsg, err := s.initAgentUC.Execute(path)
-		if err != nil {
-			return nil, &RpcError{Code: -32000, Message: err.Error()}
-		}
-		return &CallToolResult{Content: []ToolContent{{Type: "text", Text: resultMsg}}}, nil
-
-	case "asdp_init_project":
-		path, _ := callParams.Arguments["path"].(string)
-		codePath, _ := callParams.Arguments["code_path"].(string)
-		title, _ := callParams.Arguments["title"].(string)
-		summary, _ := callParams.Arguments["summary"].(string)
-		context, _ := callParams.Arguments["context"].(string)
-
-		resultMsg, err := s.initProjectUC.Execute(path, codePath, title, summary, context)
-		if err != nil {
-			return nil, &RpcError{Code: -32000, Message: err.Error()}
-		}
-		return &CallToolResult{Content: []ToolContent{{Type: "text", Text: resultMsg}}}, nil
-
-	case "asdp_validate":
-		path, _ := callParams.Arguments["path"].(string)
-		report, err := s.validateUC.Execute(path)
-		if err != nil {
-			return nil, &RpcError{Code: -32000, Message: err.Error()}
-		}
-		jsonBytes, _ := json.MarshalIndent(report, "", "  ")
-		return &CallToolResult{
-			Content: []ToolContent{{Type: "text", Text: string(jsonBytes)}},
-			IsError: !report.IsValid,
-		}, nil
-
-	case "asdp_function_info":
-		path, _ := callParams.Arguments["path"].(string)
-		symbol, _ := callParams.Arguments["symbol"].(string)
-		res, err := s.functionUC.Execute(path, symbol)
-		if err != nil {
-			return nil, &RpcError{Code: -32000, Message: err.Error()}
-		}
-		jsonBytes, _ := json.MarshalIndent(res, "", "  ")
-		return &CallToolResult{
-			Content: []ToolContent{{Type: "text", Text: string(jsonBytes)}},
-		}, nil
-
-	case "asdp_manage_exclusions":
-		path, _ := callParams.Arguments["path"].(string)
-		target, _ := callParams.Arguments["target"].(string)
-		action, _ := callParams.Arguments["action"].(string)
-
-		if path == "" || target == "" || action == "" {
-			return nil, &RpcError{Code: -32602, Message: "path, target, and action are required"}
-		}
-
-		err := s.manageExclusionsUC.Execute(path, target, action)
-		if err != nil {
-			return &CallToolResult{
-				Content: []ToolContent{
-					{
-						Type: "text",
-						Text: fmt.Sprintf("Error managing exclusions: %v", err),
-					},
-				},
-				IsError: true,
-			}, nil
-		}
-
-		return &CallToolResult{
-			Content: []ToolContent{
-				{
-					Type: "text",
-					Text: fmt.Sprintf("Successfully executed '%s' exclusion for '%s' in %s", action, target, path),
-				},
-			},
-		}, nil
-
-	default:
+	t, ok := s.findTool(callParams.Name)
+	if !ok {
 		return nil, &RpcError{Code: -32601, Message: fmt.Sprintf("Tool not found: %s", callParams.Name)}
 	}
+	args, fieldErrs := validateArguments(t.Metadata.InputSchema, callParams.Arguments)
+	if len(fieldErrs) > 0 {
+		return nil, invalidParamsError(t.Name, fieldErrs)
+	}
+	result, rpcErr := runTool(ctx, t, args)
+	if result != nil {
+		downgradeResult(result, sess.protocolFeatures())
+	}
+	return result, rpcErr
+}
+
+// runTool calls the handler of t, turning a panic into an internal error so a single
+// malformed artifact cannot bring down the server and every session with it.
+func runTool(ctx context.Context, t tool, args map[string]interface{}) (result *CallToolResult, rpcErr *RpcError) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.ErrorContext(ctx, "tool handler panicked", "tool", t.Name, "panic", r, "stack", string(debug.Stack()))
+			result, rpcErr = nil, &RpcError{Code: -32603, Message: fmt.Sprintf("Internal error in %s: %v", t.Name, r)}
+		}
+	}()
+	return t.Handler(ctx, args)
 }
@@ -0,0 +1,18 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRunToolRecoversPanic(t *testing.T) {
+	panicking := tool{Name: "asdp_boom", Handler: func(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError) {
+		var s []int
+		_ = s[3]
+		return nil, nil
+	}}
+	result, rpcErr := runTool(context.Background(), panicking, nil)
+	if result != nil || rpcErr == nil || rpcErr.Code != -32603 {
+		t.Fatalf("runTool = %v, %v; want an internal error", result, rpcErr)
+	}
+}
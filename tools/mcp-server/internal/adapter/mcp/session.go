@@ -0,0 +1,191 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"sync"
+)
+
+// session holds the per-client state shared by every transport.
+// Stdio has exactly one session; Streamable HTTP has one per Mcp-Session-Id.
+type session struct {
+	id string
+
+	mu       sync.Mutex
+	version  string                        // Protocol version agreed in initialize; "" before it
+	features protocolFeatures              // What the agreed version allows
+	out      func(msg []byte)              // Delivers server-initiated messages; nil when no stream is attached
+	inflight map[string]context.CancelFunc // Running requests, keyed by their encoded JSON-RPC id
+	subs     map[string]bool               // Resource URIs passed to resources/subscribe
+	minLevel slog.Level                    // Minimum level sent as notifications/message
+}
+
+func newSession(id string) *session {
+	return &session{
+		id:       id,
+		inflight: make(map[string]context.CancelFunc),
+		subs:     make(map[string]bool),
+		minLevel: defaultSessionLogLevel,
+	}
+}
+
+// newSessionID returns a random, URL-safe identifier for HTTP sessions.
+func newSessionID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		panic("mcp: failed to generate session id: " + err.Error())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// attach sets the sink for server-initiated messages. Passing nil detaches it.
+func (sess *session) attach(out func(msg []byte)) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.out = out
+}
+
+// attachStream attaches out unless another stream already is, and reports whether it did.
+func (sess *session) attachStream(out func(msg []byte)) bool {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.out != nil {
+		return false
+	}
+	sess.out = out
+	return true
+}
+
+// notify sends a JSON-RPC notification to the client. Messages are dropped
+// when no stream is attached (e.g. an HTTP client that never opened a GET stream).
+func (sess *session) notify(method string, params interface{}) {
+	bytes, err := encodeNotification(method, params)
+	if err != nil {
+		return
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.out != nil {
+		sess.out(bytes)
+	}
+}
+
+func encodeNotification(method string, params interface{}) ([]byte, error) {
+	return json.Marshal(JsonRpcNotification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+type sessionKey struct{}
+
+// withSession tags ctx with the session it serves, so logs emitted while serving
+// it reach that client.
+func withSession(ctx context.Context, sess *session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, sess)
+}
+
+func sessionFrom(ctx context.Context) (*session, bool) {
+	sess, ok := ctx.Value(sessionKey{}).(*session)
+	return sess, ok
+}
+
+type notifyFunc func(method string, params interface{})
+
+type notifierKey struct{}
+
+// withNotifier routes the notifications emitted while serving a request to a
+// request-scoped stream (e.g. the SSE response of an HTTP POST).
+func withNotifier(ctx context.Context, fn notifyFunc) context.Context {
+	return context.WithValue(ctx, notifierKey{}, fn)
+}
+
+// notifier returns where notifications about ctx's request should go: the request's
+// own stream when the transport opened one, the session's stream otherwise.
+func (sess *session) notifier(ctx context.Context) notifyFunc {
+	if fn, ok := ctx.Value(notifierKey{}).(notifyFunc); ok {
+		return fn
+	}
+	return sess.notify
+}
+
+// begin registers an in-flight request so notifications/cancelled can abort it.
+// The returned func must be called once the request has been answered.
+func (sess *session) begin(parent context.Context, id interface{}) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	key := requestKey(id)
+
+	sess.mu.Lock()
+	sess.inflight[key] = cancel
+	sess.mu.Unlock()
+
+	return ctx, func() {
+		sess.mu.Lock()
+		delete(sess.inflight, key)
+		sess.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancel aborts an in-flight request. Unknown or finished ids are ignored, as the spec requires.
+func (sess *session) cancel(id interface{}) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if cancel, ok := sess.inflight[requestKey(id)]; ok {
+		cancel()
+	}
+}
+
+// requestKey normalizes a JSON-RPC id so that 1 and "1" stay distinct.
+func requestKey(id interface{}) string {
+	bytes, _ := json.Marshal(id)
+	return string(bytes)
+}
+
+func (sess *session) setProtocol(version string, features protocolFeatures) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.version = version
+	sess.features = features
+}
+
+func (sess *session) protocolVersion() string {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.version
+}
+
+func (sess *session) protocolFeatures() protocolFeatures {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.features
+}
+
+func (sess *session) setLogLevel(level slog.Level) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.minLevel = level
+}
+
+func (sess *session) logLevel() slog.Level {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.minLevel
+}
+
+func (sess *session) subscribe(uri string, on bool) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if on {
+		sess.subs[uri] = true
+	} else {
+		delete(sess.subs, uri)
+	}
+}
+
+func (sess *session) isSubscribed(uri string) bool {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.subs[uri]
+}
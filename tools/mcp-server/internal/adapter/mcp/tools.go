@@ -0,0 +1,695 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Josepavese/asdp/engine/domain"
+	"github.com/Josepavese/asdp/engine/usecase"
+	"github.com/Josepavese/asdp/validate/check"
+)
+
+// toolHandler executes a tool call with the arguments sent by the client.
+type toolHandler func(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError)
+
+// tool is a registry entry: each tool declares its name, default metadata and handler once.
+// tools/list is generated from the registry merged with MCPConfig.ToolDefinitions overrides.
+type tool struct {
+	Name         string
+	Metadata     domain.ToolMetadata
+	OutputSchema map[string]interface{} // nil for tools that only return a text message
+	Handler      toolHandler
+}
+
+// registerTools builds the registry in the order tools are advertised.
+func (s *Server) registerTools() []tool {
+	return []tool{
+		{
+			Name: "asdp_query_context",
+			Metadata: domain.ToolMetadata{
+				Description: "Retrieve the ASDP context (Spec, Model, Freshness) for a given absolute path. Result: Returns a JSON object containing the merged CodeSpec (intent), CodeModel (structure), and current freshness status, allowing an agent to quickly understand a module's contract and implementation. With depth > 0, child modules are nested under 'children' so a whole subtree can be read in one call.",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":        "string",
+							"description": "ABSOLUTE path to the module (e.g. /home/user/project/module)",
+						},
+						"depth": map[string]interface{}{
+							"type":        "integer",
+							"description": "Levels of child modules to nest under 'children', each with its own freshness. Default: 0 (the module alone)",
+							"minimum":     0,
+							"maximum":     10,
+							"default":     0,
+						},
+						"max_tokens": map[string]interface{}{
+							"type":        "integer",
+							"description": "Approximate token budget for the whole result. Content is ranked (summary, spec, requirements, exported symbols, signatures, docstrings, spec body, unexported symbols, children) and packed until the budget is used; 'budget.omitted' lists what was left out. Default: unbounded, without docstrings or spec bodies",
+							"minimum":     1,
+						},
+						"include": map[string]interface{}{
+							"type":        "array",
+							"description": "Sections to return for each module. Default: all",
+							"items": map[string]interface{}{
+								"type": "string",
+								"enum": []string{usecase.IncludeSpec, usecase.IncludeModel, usecase.IncludeFreshness},
+							},
+						},
+					},
+					"required": []string{"path"},
+				},
+			},
+			OutputSchema: outputSchemaOf(domain.ContextResponse{}),
+			Handler:      s.callQueryContext,
+		},
+		{
+			Name: "asdp_sync_codemodel",
+			Metadata: domain.ToolMetadata{
+				Description: "Automatically scans the source code and updates the codemodel.md file. Result: Returns a SyncResult JSON with the count of symbols identified (functions, structs, interfaces including start/end lines) and the integrity hash of the source files (a list of SyncResults when recursive).",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":        "string",
+							"description": "ABSOLUTE path to the module (e.g. /home/user/project/module)",
+						},
+						"recursive": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Re-sync every module with a codemodel.md listed in the codetree.md at path, instead of only path itself. Default: false",
+						},
+					},
+					"required": []string{"path"},
+				},
+			},
+			OutputSchema: outputSchemaOf(syncCodeModelOutput{}),
+			Handler:      s.callSyncCodeModel,
+		},
+		{
+			Name: "asdp_sync_codetree",
+			Metadata: domain.ToolMetadata{
+				Description: "Automatically scans the project directory and updates the codetree.md file. Result: Returns a JSON representation of the project hierarchy, listing all modules and their ASDP compliance status (presence of codespec/codemodel).",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":        "string",
+							"description": "ABSOLUTE path to the project root or sub-directory.",
+						},
+					},
+					"required": []string{"path"},
+				},
+			},
+			OutputSchema: outputSchemaOf(domain.CodeTree{}),
+			Handler:      s.callSyncCodeTree,
+		},
+		{
+			Name: "asdp_scaffold",
+			Metadata: domain.ToolMetadata{
+				Description: "Create a new ASDP-compliant module or backfill missing files (codespec/codemodel) in an existing one. Safe to run on existing directories; will not overwrite existing files. Result: Returns a success message.",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name": map[string]interface{}{
+							"type":        "string",
+							"description": "Module name. Use '.' to scaffold directly in the provided path.",
+						},
+						"type": map[string]interface{}{
+							"type":        "string",
+							"description": "Module type (library, service, app). Default: library",
+							"enum":        []string{"library", "service", "app"},
+							"default":     "library",
+						},
+						"path": map[string]interface{}{
+							"type":        "string",
+							"description": "ABSOLUTE parent directory (or target directory if name='.').",
+						},
+						"title": map[string]interface{}{
+							"type":        "string",
+							"description": "Title of the module (e.g. 'User Authentication Service'). Required.",
+						},
+						"summary": map[string]interface{}{
+							"type":        "string",
+							"description": "Brief summary of the module's purpose. Required.",
+						},
+						"context": map[string]interface{}{
+							"type":        "string",
+							"description": "Detailed context and reasoning for this module. Required.",
+						},
+					},
+					"required": []string{"name", "path", "title", "summary", "context"},
+				},
+			},
+			Handler: s.callScaffold,
+		},
+		{
+			Name: "asdp_init_agent",
+			Metadata: domain.ToolMetadata{
+				Description: "Copies ASDP Agent assets into the local project. Result: Returns a list of files copied into the .agent/ directory (Rules, Workflows, etc.) to enable ASDP-native agent behavior.",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":        "string",
+							"description": "ABSOLUTE project root path (e.g. /home/user/project)",
+						},
+					},
+				},
+			},
+			Handler: s.callInitAgent,
+		},
+		{
+			Name: "asdp_init_project",
+			Metadata: domain.ToolMetadata{
+				Description: "Unified initialization of an ASDP project. Result: Sets up .agent/ at project path AND anchors the CodeTree. You MUST analyze the project structure and README first, then provide meaningful Title, Summary, and Context.",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":        "string",
+							"description": "ABSOLUTE project repository root path.",
+						},
+						"code_path": map[string]interface{}{
+							"type":        "string",
+							"description": "ABSOLUTE path to where the actual code starts (e.g. /repo/tools).",
+						},
+						"title": map[string]interface{}{
+							"type":        "string",
+							"description": "Descriptive title for the root module (at least 3 characters). You MUST analyze the project first to generate this.",
+							"minLength":   3,
+						},
+						"summary": map[string]interface{}{
+							"type":        "string",
+							"description": "Brief summary of the root module (at least 10 characters). You MUST analyze the project first to generate this.",
+							"minLength":   10,
+						},
+						"context": map[string]interface{}{
+							"type":        "string",
+							"description": "Detailed context and reasoning for the root module (at least 20 characters). You MUST analyze the project first to generate this.",
+							"minLength":   20,
+						},
+					},
+					"required": []string{"path", "code_path", "title", "summary", "context"},
+				},
+			},
+			Handler: s.callInitProject,
+		},
+		{
+			Name: "asdp_validate",
+			Metadata: domain.ToolMetadata{
+				Description: "Audit the ASDP project state. Returns a report of Errors (invalid state, integration blocking) and Warnings (staleness). Checks for mandatory files, strict content compliance, and synchronization freshness.",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":        "string",
+							"description": "ABSOLUTE path to the project root.",
+						},
+					},
+					"required": []string{"path"},
+				},
+			},
+			OutputSchema: outputSchemaOf(check.ValidationReport{}),
+			Handler:      s.callValidate,
+		},
+		{
+			Name: "asdp_function_info",
+			Metadata: domain.ToolMetadata{
+				Description: "Retrieve detailed information about a function/symbol, including its source code, documentation, and the codespec/codemodel context of its module. When the symbol is ambiguous (e.g. a method name shared by several types), returns the list of 'candidates' instead; ask again with 'Parent.Name' or 'file:line'.",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":        "string",
+							"description": "ABSOLUTE path to the module containing the symbol.",
+						},
+						"symbol": map[string]interface{}{
+							"type":        "string",
+							"description": "The symbol to inspect: its name, 'Parent.Name' for a method of a given receiver/class (e.g. 'Server.Serve'), or 'file:line' for the symbol whose range holds that line (e.g. 'server.go:95').",
+						},
+						"kind": map[string]interface{}{
+							"type":        "string",
+							"description": "Optional: only consider symbols of this kind (e.g. function, method, struct).",
+						},
+						"details": map[string]interface{}{
+							"type":        "boolean",
+							"default":     false,
+							"description": "Also return the leading doc comment and the functions of the same module that call it (callers) or that it calls (callees), matched by name.",
+						},
+					},
+					"required": []string{"path", "symbol"},
+				},
+			},
+			OutputSchema: outputSchemaOf(usecase.FunctionInfoResponse{}),
+			Handler:      s.callFunctionInfo,
+		},
+		{
+			Name: "asdp_call_graph",
+			Metadata: domain.ToolMetadata{
+				Description: "Walk the static call graph of a Go module from a function or method: who calls it (callers) and what it calls (callees), within the module, up to a given depth. Calls through an interface of the module also lead to the methods implementing it (marked 'dispatch'). Needs a codemodel synced with parsing.go.call_graph. When the symbol is ambiguous, returns the list of 'candidates' instead.",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":        "string",
+							"description": "ABSOLUTE path to the module containing the symbol.",
+						},
+						"symbol": map[string]interface{}{
+							"type":        "string",
+							"description": "The function or method: its name, 'Parent.Name' (e.g. 'Server.Serve'), or 'file:line' (e.g. 'server.go:95').",
+						},
+						"direction": map[string]interface{}{
+							"type":        "string",
+							"enum":        []string{usecase.CallDirectionCallers, usecase.CallDirectionCallees, usecase.CallDirectionBoth},
+							"default":     usecase.CallDirectionBoth,
+							"description": "Follow the calls towards the callers, the callees or both.",
+						},
+						"depth": map[string]interface{}{
+							"type":        "integer",
+							"minimum":     1,
+							"maximum":     10,
+							"default":     1,
+							"description": "Levels of calls to follow; 1 returns the direct callers or callees only.",
+						},
+						"kind": map[string]interface{}{
+							"type":        "string",
+							"description": "Optional: only consider symbols of this kind (e.g. function, method).",
+						},
+					},
+					"required": []string{"path", "symbol"},
+				},
+			},
+			OutputSchema: outputSchemaOf(usecase.CallGraphResponse{}),
+			Handler:      s.callCallGraph,
+		},
+		{
+			Name: "asdp_dependents",
+			Metadata: domain.ToolMetadata{
+				Description: "Find the modules that depend on a given module, directly or transitively, from the dependencies declared in every codespec.md of the codetree. Result: Returns direct and transitive dependents with the stated reason, plus project-wide dangling references to modules that do not exist. Use it before refactoring a module's contract.",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":        "string",
+							"description": "ABSOLUTE path to the project root (where codetree.md lives).",
+						},
+						"module": map[string]interface{}{
+							"type":        "string",
+							"description": "The module to inspect: its ABSOLUTE path, its path relative to the project root, or its codespec id.",
+						},
+					},
+					"required": []string{"path", "module"},
+				},
+			},
+			OutputSchema: outputSchemaOf(usecase.DependentsResponse{}),
+			Handler:      s.callDependents,
+		},
+		{
+			Name: "asdp_find_symbol",
+			Metadata: domain.ToolMetadata{
+				Description: "Search the symbols of every codemodel.md in the codetree, without knowing which module holds them. Result: Returns the matching symbols with their module, file, line range and signature. Use 'Parent.Name' (e.g. 'Server.Serve') to select a method by its receiver, and match 'prefix' or 'fuzzy' when the exact name is unknown.",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":        "string",
+							"description": "ABSOLUTE path to the project root (where codetree.md lives).",
+						},
+						"query": map[string]interface{}{
+							"type":        "string",
+							"description": "Symbol name, or 'Parent.Name' to also match the parent (receiver type or class).",
+						},
+						"match": map[string]interface{}{
+							"type":        "string",
+							"enum":        []string{"exact", "prefix", "fuzzy"},
+							"default":     "exact",
+							"description": "exact (case-sensitive), prefix or fuzzy (case-insensitive; fuzzy ranks the best matches first).",
+						},
+						"kind": map[string]interface{}{
+							"type":        "string",
+							"description": "Optional: only symbols of this kind (e.g. function, method, struct, interface, field, const, var).",
+						},
+						"exported": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Optional: only exported (true) or unexported (false) symbols.",
+						},
+						"limit": map[string]interface{}{
+							"type":        "integer",
+							"minimum":     1,
+							"maximum":     500,
+							"default":     50,
+							"description": "Maximum number of matches to return.",
+						},
+					},
+					"required": []string{"path", "query"},
+				},
+			},
+			OutputSchema: outputSchemaOf(usecase.FindSymbolResponse{}),
+			Handler:      s.callFindSymbol,
+		},
+		{
+			Name: "asdp_search",
+			Metadata: domain.ToolMetadata{
+				Description: "Full-text search over the prose of every codespec.md and codemodel.md in the codetree: titles, summaries, capabilities, requirements, spec bodies (ADRs, rationale) and codemodel annotations. Result: Returns ranked hits with the module, file, field, section and a snippet. Use it to find where a concept or decision is documented.",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":        "string",
+							"description": "ABSOLUTE path to the project root (where codetree.md lives).",
+						},
+						"query": map[string]interface{}{
+							"type":        "string",
+							"description": "Words to search for; hits containing all of them rank first.",
+						},
+						"limit": map[string]interface{}{
+							"type":        "integer",
+							"minimum":     1,
+							"maximum":     200,
+							"default":     20,
+							"description": "Maximum number of hits to return.",
+						},
+					},
+					"required": []string{"path", "query"},
+				},
+			},
+			OutputSchema: outputSchemaOf(usecase.SearchResponse{}),
+			Handler:      s.callSearch,
+		},
+		{
+			Name: "asdp_manage_exclusions",
+			Metadata: domain.ToolMetadata{
+				Description: "This is a tool from the asdp MCP server.\nExclude specific folders or branches from the ASDP protocol to hide them from context scanning. This modifies codetree.md.",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":        "string",
+							"description": "ABSOLUTE project root path.",
+						},
+						"target": map[string]interface{}{
+							"type":        "string",
+							"description": "Name of the folder or relative path to exclude (e.g. 'dist', 'legacy', 'temp/build').",
+						},
+						"action": map[string]interface{}{
+							"type":        "string",
+							"description": "Action to perform: 'add' or 'remove'. Default: add",
+							"enum":        []string{"add", "remove"},
+							"default":     "add",
+						},
+					},
+					"required": []string{"path", "target"},
+				},
+			},
+			Handler: s.callManageExclusions,
+		},
+	}
+}
+
+// activeTools applies the MCPConfig.ToolDefinitions overrides to the registry:
+// a non-empty description or schema replaces the default, and disabled tools are dropped.
+func (s *Server) activeTools() []tool {
+	var active []tool
+	for _, t := range s.tools {
+		override, ok := s.config.MCP.ToolDefinitions[t.Name]
+		if ok {
+			if override.Disabled {
+				continue
+			}
+			if override.Description != "" {
+				t.Metadata.Description = override.Description
+			}
+			if override.InputSchema != nil {
+				t.Metadata.InputSchema = override.InputSchema
+			}
+		}
+		active = append(active, t)
+	}
+	return active
+}
+
+func (s *Server) findTool(name string) (tool, bool) {
+	for _, t := range s.activeTools() {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return tool{}, false
+}
+
+// syncCodeModelOutput wraps sync results so single and recursive syncs share one
+// outputSchema (structuredContent must be an object).
+type syncCodeModelOutput struct {
+	Results []*usecase.SyncResult `json:"results"`
+}
+
+// --- Tool Handlers ---
+
+func (s *Server) callQueryContext(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError) {
+	path, _ := args["path"].(string)
+	opts := usecase.QueryOptions{}
+	if depth, ok := args["depth"].(float64); ok {
+		opts.Depth = int(depth)
+	}
+	if maxTokens, ok := args["max_tokens"].(float64); ok {
+		opts.MaxTokens = int(maxTokens)
+	}
+	if include, ok := args["include"].([]interface{}); ok {
+		for _, section := range include {
+			if name, ok := section.(string); ok {
+				opts.Include = append(opts.Include, name)
+			}
+		}
+	}
+	resp, err := s.queryUC.ExecuteWithOptions(ctx, path, opts)
+	if err != nil {
+		return nil, &RpcError{Code: -32000, Message: err.Error()}
+	}
+	result := jsonResult(resp, resp.Validation != nil && !resp.Validation.IsValid)
+
+	// Point at the module's resources so clients can subscribe to them
+	var artifacts []string
+	if resp.Spec.MetaData.Title != "" {
+		artifacts = append(artifacts, artifactSpec)
+	}
+	if resp.Model.MetaData.Integrity.SrcHash != "" {
+		artifacts = append(artifacts, artifactModel)
+	}
+	for _, artifact := range artifacts {
+		if uri, ok := s.moduleResourceURI(resp.Path, artifact); ok {
+			result.Content = append(result.Content, ToolContent{
+				Type:     "resource_link",
+				URI:      uri,
+				Name:     artifact,
+				MimeType: jsonMimeType,
+			})
+		}
+	}
+	return result, nil
+}
+
+func (s *Server) callSyncCodeModel(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError) {
+	path, _ := args["path"].(string)
+	recursive, _ := args["recursive"].(bool)
+	if !recursive {
+		res, err := s.syncUC.Execute(ctx, path)
+		if err != nil {
+			return nil, &RpcError{Code: -32000, Message: err.Error()}
+		}
+		result := jsonResult(res, false)
+		result.StructuredContent = syncCodeModelOutput{Results: []*usecase.SyncResult{res}}
+		return result, nil
+	}
+
+	results, err := s.syncUC.ExecuteAll(ctx, path)
+	if err != nil {
+		return nil, &RpcError{Code: -32000, Message: err.Error()}
+	}
+	result := jsonResult(results, false)
+	result.StructuredContent = syncCodeModelOutput{Results: results}
+	return result, nil
+}
+
+func (s *Server) callSyncCodeTree(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError) {
+	path, _ := args["path"].(string)
+	res, err := s.syncTreeUC.Execute(ctx, path)
+	if err != nil {
+		return nil, &RpcError{Code: -32000, Message: err.Error()}
+	}
+
+	// Recursive check for validation errors anywhere in the tree
+	hasAnyError := false
+	var checkErrors func(c []domain.Component)
+	checkErrors = func(comps []domain.Component) {
+		for _, c := range comps {
+			if !c.IsValid {
+				hasAnyError = true
+				return
+			}
+			checkErrors(c.Children)
+		}
+	}
+	checkErrors(res.MetaData.Components)
+
+	return jsonResult(res, hasAnyError), nil
+}
+
+func (s *Server) callScaffold(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError) {
+	name, _ := args["name"].(string)
+	modType, _ := args["type"].(string)
+	path, _ := args["path"].(string)
+	title, _ := args["title"].(string)
+	summary, _ := args["summary"].(string)
+	moduleContext, _ := args["context"].(string)
+	if name == "" {
+		return nil, &RpcError{Code: -32602, Message: "Missing required argument: name"}
+	}
+	if modType == "" {
+		modType = "library"
+	}
+	resultMsg, err := s.scaffoldUC.Execute(usecase.ScaffoldParams{
+		Name:    name,
+		Type:    modType,
+		Path:    path,
+		Title:   title,
+		Summary: summary,
+		Context: moduleContext,
+	})
+	if err != nil {
+		return nil, &RpcError{Code: -32000, Message: err.Error()}
+	}
+	return &CallToolResult{Content: []ToolContent{{Type: "text", Text: resultMsg}}}, nil
+}
+
+func (s *Server) callInitAgent(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError) {
+	path, _ := args["path"].(string)
+	resultMsg, err := s.initAgentUC.Execute(path)
+	if err != nil {
+		return nil, &RpcError{Code: -32000, Message: err.Error()}
+	}
+	return &CallToolResult{Content: []ToolContent{{Type: "text", Text: resultMsg}}}, nil
+}
+
+func (s *Server) callInitProject(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError) {
+	path, _ := args["path"].(string)
+	codePath, _ := args["code_path"].(string)
+	title, _ := args["title"].(string)
+	summary, _ := args["summary"].(string)
+	moduleContext, _ := args["context"].(string)
+
+	resultMsg, err := s.initProjectUC.Execute(ctx, path, codePath, title, summary, moduleContext)
+	if err != nil {
+		return nil, &RpcError{Code: -32000, Message: err.Error()}
+	}
+	return &CallToolResult{Content: []ToolContent{{Type: "text", Text: resultMsg}}}, nil
+}
+
+func (s *Server) callValidate(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError) {
+	path, _ := args["path"].(string)
+	report, err := s.validateUC.Execute(ctx, path)
+	if err != nil {
+		return nil, &RpcError{Code: -32000, Message: err.Error()}
+	}
+	return jsonResult(report, !report.IsValid), nil
+}
+
+func (s *Server) callFunctionInfo(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError) {
+	path, _ := args["path"].(string)
+	symbol, _ := args["symbol"].(string)
+	opts := usecase.FunctionInfoOptions{}
+	opts.Kind, _ = args["kind"].(string)
+	opts.Details, _ = args["details"].(bool)
+	res, err := s.functionUC.ExecuteWithOptions(ctx, path, symbol, opts)
+	if err != nil {
+		return nil, &RpcError{Code: -32000, Message: err.Error()}
+	}
+	return jsonResult(res, false), nil
+}
+
+func (s *Server) callDependents(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError) {
+	path, _ := args["path"].(string)
+	module, _ := args["module"].(string)
+	res, err := s.dependentsUC.Execute(ctx, path, module)
+	if err != nil {
+		return nil, &RpcError{Code: -32000, Message: err.Error()}
+	}
+	return jsonResult(res, false), nil
+}
+
+func (s *Server) callCallGraph(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError) {
+	path, _ := args["path"].(string)
+	symbol, _ := args["symbol"].(string)
+	opts := usecase.CallGraphOptions{}
+	opts.Direction, _ = args["direction"].(string)
+	opts.Kind, _ = args["kind"].(string)
+	if depth, ok := args["depth"].(float64); ok {
+		opts.Depth = int(depth)
+	}
+
+	res, err := s.callGraphUC.Execute(ctx, path, symbol, opts)
+	if err != nil {
+		return nil, &RpcError{Code: -32000, Message: err.Error()}
+	}
+	return jsonResult(res, false), nil
+}
+
+func (s *Server) callFindSymbol(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError) {
+	path, _ := args["path"].(string)
+	query := usecase.SymbolQuery{}
+	query.Query, _ = args["query"].(string)
+	query.Match, _ = args["match"].(string)
+	query.Kind, _ = args["kind"].(string)
+	if exported, ok := args["exported"].(bool); ok {
+		query.Exported = &exported
+	}
+	if limit, ok := args["limit"].(float64); ok {
+		query.Limit = int(limit)
+	}
+
+	res, err := s.findSymbolUC.Execute(ctx, path, query)
+	if err != nil {
+		return nil, &RpcError{Code: -32000, Message: err.Error()}
+	}
+	return jsonResult(res, false), nil
+}
+
+func (s *Server) callSearch(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError) {
+	path, _ := args["path"].(string)
+	query, _ := args["query"].(string)
+	limit := 0
+	if l, ok := args["limit"].(float64); ok {
+		limit = int(l)
+	}
+	res, err := s.searchUC.Execute(ctx, path, query, limit)
+	if err != nil {
+		return nil, &RpcError{Code: -32000, Message: err.Error()}
+	}
+	return jsonResult(res, false), nil
+}
+
+func (s *Server) callManageExclusions(ctx context.Context, args map[string]interface{}) (*CallToolResult, *RpcError) {
+	path, _ := args["path"].(string)
+	target, _ := args["target"].(string)
+	action, _ := args["action"].(string)
+
+	err := s.manageExclusionsUC.Execute(ctx, path, target, action)
+	if err != nil {
+		return &CallToolResult{
+			Content: []ToolContent{
+				{
+					Type: "text",
+					Text: fmt.Sprintf("Error managing exclusions: %v", err),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	return &CallToolResult{
+		Content: []ToolContent{
+			{
+				Type: "text",
+				Text: fmt.Sprintf("Successfully executed '%s' exclusion for '%s' in %s", action, target, path),
+			},
+		},
+	}, nil
+}
@@ -17,10 +17,16 @@ type JsonRpcResponse struct {
 	ID      interface{} `json:"id"`
 }
 
+type JsonRpcNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
 type RpcError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Data    string `json:"data,omitempty"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
 // MCP Specific Types
@@ -39,39 +45,188 @@ type InitializeParams struct {
 }
 
 type InitializeResult struct {
-	ProtocolVersion string `json:"protocolVersion"`
-	Capabilities    struct {
-		Tools *struct {
-			ListChanged bool `json:"listChanged"`
-		} `json:"tools,omitempty"`
-	} `json:"capabilities"`
-	ServerInfo struct {
+	ProtocolVersion string             `json:"protocolVersion"`
+	Capabilities    ServerCapabilities `json:"capabilities"`
+	ServerInfo      struct {
 		Name    string `json:"name"`
 		Version string `json:"version"`
 	} `json:"serverInfo"`
 }
 
+type ServerCapabilities struct {
+	Tools     *ListChangedCapability `json:"tools,omitempty"`
+	Resources *ResourcesCapability   `json:"resources,omitempty"`
+	Prompts   *ListChangedCapability `json:"prompts,omitempty"`
+	Logging   *struct{}              `json:"logging,omitempty"`
+}
+
+type ListChangedCapability struct {
+	ListChanged bool `json:"listChanged"`
+}
+
+type ResourcesCapability struct {
+	Subscribe   bool `json:"subscribe"`
+	ListChanged bool `json:"listChanged"`
+}
+
 type CallToolParams struct {
 	Name      string                 `json:"name"`
 	Arguments map[string]interface{} `json:"arguments"`
+	Meta      *RequestMeta           `json:"_meta,omitempty"`
+}
+
+type RequestMeta struct {
+	ProgressToken interface{} `json:"progressToken,omitempty"`
+}
+
+type ProgressParams struct {
+	ProgressToken interface{} `json:"progressToken"`
+	Progress      int         `json:"progress"`
+	Total         int         `json:"total,omitempty"`
+	Message       string      `json:"message,omitempty"`
 }
 
 type CallToolResult struct {
-	Content []ToolContent `json:"content"`
-	IsError bool          `json:"isError,omitempty"`
+	Content           []ToolContent `json:"content"`
+	StructuredContent interface{}   `json:"structuredContent,omitempty"` // Since 2025-06-18; matches the tool's outputSchema
+	IsError           bool          `json:"isError,omitempty"`
 }
 
+// ToolContent is a "text" item, or a "resource_link" one (since 2025-06-18).
+// MarshalJSON writes the fields of its type only, the required ones even when empty.
 type ToolContent struct {
-	Type string `json:"type"` // "text"
+	Type string `json:"type"` // "text" or "resource_link"
+	Text string `json:"text"` // Required for "text"
+
+	// resource_link fields
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type textContent struct {
+	Type string `json:"type"`
 	Text string `json:"text"`
 }
 
+type resourceLinkContent struct {
+	Type        string `json:"type"`
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+func (c ToolContent) MarshalJSON() ([]byte, error) {
+	if c.Type == "resource_link" {
+		return json.Marshal(resourceLinkContent{Type: c.Type, URI: c.URI, Name: c.Name, Description: c.Description, MimeType: c.MimeType})
+	}
+	return json.Marshal(textContent{Type: c.Type, Text: c.Text})
+}
+
+type SetLevelParams struct {
+	Level string `json:"level"`
+}
+
+type LoggingMessageParams struct {
+	Level  string      `json:"level"`
+	Logger string      `json:"logger,omitempty"`
+	Data   interface{} `json:"data"`
+}
+
+type CancelledParams struct {
+	RequestID interface{} `json:"requestId"`
+	Reason    string      `json:"reason,omitempty"`
+}
+
 type ListToolsResult struct {
 	Tools []ToolDefinition `json:"tools"`
 }
 
 type ToolDefinition struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	InputSchema interface{} `json:"inputSchema"`
+	Name         string      `json:"name"`
+	Description  string      `json:"description"`
+	InputSchema  interface{} `json:"inputSchema"`
+	OutputSchema interface{} `json:"outputSchema,omitempty"`
+}
+
+// Resources
+
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type ListResourcesResult struct {
+	Resources []Resource `json:"resources"`
+}
+
+type ListResourceTemplatesResult struct {
+	ResourceTemplates []ResourceTemplate `json:"resourceTemplates"`
+}
+
+type ReadResourceParams struct {
+	URI string `json:"uri"`
+}
+
+type ReadResourceResult struct {
+	Contents []ResourceContents `json:"contents"`
+}
+
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+// SubscribeParams is shared by resources/subscribe and resources/unsubscribe.
+type SubscribeParams struct {
+	URI string `json:"uri"`
+}
+
+type ResourceUpdatedParams struct {
+	URI string `json:"uri"`
+}
+
+// Prompts
+
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type ListPromptsResult struct {
+	Prompts []Prompt `json:"prompts"`
+}
+
+type GetPromptParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+type GetPromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+type PromptMessage struct {
+	Role    string      `json:"role"` // "user", "assistant"
+	Content ToolContent `json:"content"`
 }
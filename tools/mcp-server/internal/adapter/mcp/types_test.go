@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestToolContentJSON(t *testing.T) {
+	tests := []struct {
+		content ToolContent
+		want    string
+	}{
+		{ToolContent{Type: "text", Text: "hello"}, `{"type":"text","text":"hello"}`},
+		{ToolContent{Type: "text"}, `{"type":"text","text":""}`},
+		{
+			ToolContent{Type: "resource_link", URI: "asdp://module/store/codespec", Name: "codespec", MimeType: "application/json"},
+			`{"type":"resource_link","uri":"asdp://module/store/codespec","name":"codespec","mimeType":"application/json"}`,
+		},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("%+v marshals to %s, want %s", tt.content, data, tt.want)
+		}
+	}
+
+	// Inside a result, where items are marshaled by value from a slice
+	data, err := json.Marshal(CallToolResult{Content: []ToolContent{{Type: "text"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"content":[{"type":"text","text":""}]}`; string(data) != want {
+		t.Errorf("result marshals to %s, want %s", data, want)
+	}
+}
@@ -1,12 +1,13 @@
 package check
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/Josepavese/asdp/engine/domain"
+	"github.com/Josepavese/asdp/engine/usecase"
 	"gopkg.in/yaml.v3"
 )
 
@@ -44,7 +45,7 @@ type ValidationWarning struct {
 	Reason string `json:"reason"`
 }
 
-func (uc *ValidateProjectUseCase) Execute(rootPath string) (*ValidationReport, error) {
+func (uc *ValidateProjectUseCase) Execute(ctx context.Context, rootPath string) (*ValidationReport, error) {
 	report := &ValidationReport{
 		Errors:   []ValidationError{},
 		Warnings: []ValidationWarning{},
@@ -85,16 +86,26 @@ func (uc *ValidateProjectUseCase) Execute(rootPath string) (*ValidationReport, e
 	}
 
 	// 2. Walk Tree
+	freshness := usecase.NewFreshnessService(uc.fs, uc.hasher, *config)
+	visited, checked := 0, 0
 	err = uc.fs.Walk(rootPath, func(path string, isDir bool) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if !isDir {
 			return nil
 		}
 		if uc.shouldIgnoreDir(path, rootPath, exclusions) {
 			return filepath.SkipDir // Skip directory content if ignored
 		}
+		visited++
 
 		// Analyze folder "significance"
 		isSignificant, _, isLeaf := uc.analyzeFolderSignificance(path, config.Validation.Freshness)
+		if isSignificant {
+			checked++
+		}
+		domain.ReportProgress(ctx, visited, 0, fmt.Sprintf("%d directories visited, %d modules checked", visited, checked))
 		if !isSignificant {
 			return nil
 		}
@@ -131,7 +142,9 @@ func (uc *ValidateProjectUseCase) Execute(rootPath string) (*ValidationReport, e
 				}
 
 				// C. Freshness Check (Warning)
-				uc.checkFreshness(path, specPath, filepath.Join(path, "codemodel.md"), config.Validation.Freshness, report)
+				if err := uc.checkFreshness(ctx, freshness, path, report); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -187,68 +200,40 @@ func (uc *ValidateProjectUseCase) validateSpecContent(path, content string, conf
 	return nil
 }
 
-func (uc *ValidateProjectUseCase) checkFreshness(dirPath, specPath, modelPath string, freshness domain.FreshnessConfig, report *ValidationReport) {
-	// 1. Get latest Code modification time in dir
-	files, err := uc.fs.ReadDir(dirPath)
+// checkFreshness turns the hash-based freshness of a module into warnings. Touched but
+// unchanged files (mtime_drift) are not worth one.
+func (uc *ValidateProjectUseCase) checkFreshness(ctx context.Context, freshness *usecase.FreshnessService, dirPath string, report *ValidationReport) error {
+	status, err := freshness.CheckModule(ctx, dirPath)
 	if err != nil {
-		return
+		return err
 	}
-
-	var maxCodeTime time.Time
-	for _, f := range files {
-		if f.IsDir() {
+	for _, issue := range status.Issues {
+		var artifact string
+		switch issue.Code {
+		case domain.FreshnessNeverSynced, domain.FreshnessHashDrift:
+			artifact = "CodeModel"
+		case domain.FreshnessSpecBehindModel:
+			artifact = "CodeSpec"
+		default:
 			continue
 		}
-		name := f.Name()
-
-		isWatched := false
-		for _, ext := range freshness.WatchedExtensions {
-			if strings.HasSuffix(name, ext) {
-				isWatched = true
-				break
-			}
-		}
-
-		isIgnored := false
-		for _, ext := range freshness.IgnoredExtensions {
-			if strings.HasSuffix(name, ext) {
-				isIgnored = true
-				break
-			}
-		}
-
-		if isWatched && !isIgnored {
-			if f.ModTime().After(maxCodeTime) {
-				maxCodeTime = f.ModTime()
-			}
+		reason := fmt.Sprintf("Stale %s [%s]: %s", artifact, issue.Code, issue.Message)
+		if len(issue.Files) > 0 {
+			reason += fmt.Sprintf(" (%s)", summarizeFiles(issue.Files, maxListedFiles))
 		}
+		report.Warnings = append(report.Warnings, ValidationWarning{Path: dirPath, Reason: reason})
 	}
+	return nil
+}
 
-	if maxCodeTime.IsZero() {
-		return // No code to compare against
-	}
+// maxListedFiles bounds the files named in one warning.
+const maxListedFiles = 5
 
-	// 2. Check CodeSpec Freshness
-	specInfo, err := uc.fs.Stat(specPath)
-	if err == nil {
-		if specInfo.ModTime().Before(maxCodeTime) {
-			report.Warnings = append(report.Warnings, ValidationWarning{
-				Path:   dirPath,
-				Reason: fmt.Sprintf("Stale CodeSpec: codespec.md (%v) is older than source code (%v)", specInfo.ModTime().Format(time.RFC3339), maxCodeTime.Format(time.RFC3339)),
-			})
-		}
-	}
-
-	// 3. Check CodeModel Freshness
-	modelInfo, err := uc.fs.Stat(modelPath)
-	if err == nil {
-		if modelInfo.ModTime().Before(maxCodeTime) {
-			report.Warnings = append(report.Warnings, ValidationWarning{
-				Path:   dirPath,
-				Reason: fmt.Sprintf("Stale CodeModel: codemodel.md (%v) is older than source code (%v)", modelInfo.ModTime().Format(time.RFC3339), maxCodeTime.Format(time.RFC3339)),
-			})
-		}
+func summarizeFiles(files []string, max int) string {
+	if len(files) <= max {
+		return strings.Join(files, ", ")
 	}
+	return fmt.Sprintf("%s and %d more", strings.Join(files[:max], ", "), len(files)-max)
 }
 
 func (uc *ValidateProjectUseCase) analyzeFolderSignificance(path string, freshness domain.FreshnessConfig) (isSignificant bool, isHub bool, isLeaf bool) {